@@ -0,0 +1,233 @@
+// internal/usecase/approvals.go
+package usecase
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "math/big"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+    pendingPrefix = "pending/"
+
+    defaultApprovalTTL = 24 * time.Hour
+)
+
+// approvalPolicy makes transfers above a threshold wait for approvals of distinct entities.
+type approvalPolicy struct {
+    Required   int               `json:"required_approvals"`
+    Thresholds map[string]string `json:"thresholds"` // asset → base units
+    TTL        time.Duration     `json:"ttl"`
+}
+
+// approval records one entity signing off a pending request.
+type approval struct {
+    EntityID string    `json:"entity_id"`
+    At       time.Time `json:"at"`
+}
+
+// pendingRequest is a transfer waiting for approvals. The original request is
+// kept verbatim and replayed once enough approvals are in, pinned to the key
+// version and valid_until it was submitted with. The replay is refused unless
+// it signs the same messages for the same wallet, as recorded by Fingerprint.
+type pendingRequest struct {
+    ID                 string                   `json:"id"`
+    ServiceName        string                   `json:"service_name"`
+    KeyID              int                      `json:"key_id"`
+    Address            string                   `json:"address"`
+    ValidUntil         time.Time                `json:"valid_until"`
+    Path               string                   `json:"path"`
+    Data               map[string]interface{}   `json:"data"`
+    Preview            []map[string]interface{} `json:"preview"`
+    Details            map[string]interface{}   `json:"details,omitempty"`
+    Actions            []map[string]interface{} `json:"actions,omitempty"`
+    Fingerprint        string                   `json:"fingerprint"`
+    // RequestFingerprint is the idempotency fingerprint of the submitted request, kept
+    // to replace its stored pending response by the signed one.
+    RequestFingerprint string                   `json:"request_fingerprint,omitempty"`
+    RequestedBy        string                   `json:"requested_by"`
+    Required           int                      `json:"required_approvals"`
+    Approvals          []approval               `json:"approvals"`
+    CreatedAt          time.Time                `json:"created_at"`
+    ExpiresAt          time.Time                `json:"expires_at"`
+}
+
+// approvedRequestKey marks the context of a replayed pending request, which is signed without a new approval round.
+// Its value is the *pendingRequest.
+type approvedRequestKey struct{}
+
+// approvalFingerprint hashes what an approved request signs: the wallet, the
+// message expiry, the decoded messages and the body details and actions.
+func approvalFingerprint(kp *KeyPair, validUntil time.Time, preview []map[string]interface{}, params *txnParams) (string, error) {
+    raw, err := json.Marshal(map[string]interface{}{
+        "key_id":         kp.ID,
+        "address":        kp.Address,
+        "wallet_version": kp.WalletVersion,
+        "network":        kp.Network,
+        "workchain":      kp.Workchain,
+        "valid_until":    validUntil.Unix(),
+        "preview":        preview,
+        "details":        params.details,
+        "actions":        params.actions,
+    })
+    if err != nil {
+        return "", err
+    }
+    sum := sha256.Sum256(raw)
+    return hex.EncodeToString(sum[:]), nil
+}
+
+// matches refuses the replay of a request that no longer signs what was approved,
+// such as after a change of the jetton allowlist or the wallet of the key.
+func (p *pendingRequest) matches(kp *KeyPair, validUntil time.Time, preview []map[string]interface{}, params *txnParams) error {
+    fingerprint, err := approvalFingerprint(kp, validUntil, preview, params)
+    if err != nil {
+        return err
+    }
+    if fingerprint != p.Fingerprint {
+        return fmt.Errorf("pending request %q no longer signs what was approved; cancel it and submit it again", p.ID)
+    }
+    return nil
+}
+
+// requires reports whether amounts exceed a threshold of the policy.
+func (p *approvalPolicy) requires(amounts map[string]*big.Int) bool {
+    if p == nil {
+        return false
+    }
+    for asset, amount := range amounts {
+        s, ok := p.Thresholds[asset]
+        if !ok {
+            continue
+        }
+        threshold, _ := new(big.Int).SetString(s, 10)
+        if amount.Cmp(threshold) > 0 {
+            return true
+        }
+    }
+    return false
+}
+
+// requiresBalance reports whether msgs need approvals by sending the whole wallet
+// balance, which exceeds any TON threshold whatever the amount of the message.
+func (p *approvalPolicy) requiresBalance(msgs []*outMessage) bool {
+    if p == nil {
+        return false
+    }
+    if _, ok := p.Thresholds[assetTON]; !ok {
+        return false
+    }
+    for _, m := range msgs {
+        if m.carriesBalance() {
+            return true
+        }
+    }
+    return false
+}
+
+// hasJettonThresholds reports whether the policy applies to any jetton.
+func (p *approvalPolicy) hasJettonThresholds() bool {
+    if p == nil {
+        return false
+    }
+    for asset := range p.Thresholds {
+        if asset != assetTON {
+            return true
+        }
+    }
+    return false
+}
+
+// approvedBy reports whether entity already approved the request.
+func (p *pendingRequest) approvedBy(entity string) bool {
+    for _, a := range p.Approvals {
+        if a.EntityID == entity {
+            return true
+        }
+    }
+    return false
+}
+
+func (p *pendingRequest) info(now time.Time) map[string]interface{} {
+    approvers := make([]string, len(p.Approvals))
+    for i, a := range p.Approvals {
+        approvers[i] = a.EntityID
+    }
+    return map[string]interface{}{
+        "id":                 p.ID,
+        "service_name":       p.ServiceName,
+        "key_id":             p.KeyID,
+        "address":            p.Address,
+        "valid_until":        p.ValidUntil.Unix(),
+        "path":               p.Path,
+        "preview":            p.Preview,
+        "details":            p.Details,
+        "actions":            p.Actions,
+        "requested_by":       p.RequestedBy,
+        "required_approvals": p.Required,
+        "approvals":          len(p.Approvals),
+        "approved_by":        approvers,
+        "created_at":         p.CreatedAt.Format(time.RFC3339),
+        "expires_at":         p.ExpiresAt.Format(time.RFC3339),
+        "expired":            !now.Before(p.ExpiresAt),
+    }
+}
+
+// preview is the decoded view of a message shown to approvers.
+func (m *outMessage) preview() map[string]interface{} {
+    out := map[string]interface{}{
+        "kind":        m.Kind,
+        "destination": m.Destination.ToRaw(),
+        "amount":      fmt.Sprint(m.Amount),
+    }
+    if m.Comment != "" {
+        out["comment"] = m.Comment
+    }
+    if m.JettonAmount != nil {
+        out["jetton_amount"] = m.JettonAmount.String()
+    }
+    if m.JettonMaster != nil {
+        out["jetton_master"] = m.JettonMaster.ToRaw()
+    }
+    if m.Recipient != nil {
+        out["recipient"] = m.Recipient.ToRaw()
+    }
+    if m.ResponseDestination != nil {
+        out["response_destination"] = m.ResponseDestination.ToRaw()
+    }
+    return out
+}
+
+func pendingKey(svc, id string) string {
+    return pendingPrefix + svc + "/" + id
+}
+
+func (b *Backend) pendingRequest(ctx context.Context, s logical.Storage, svc, id string) (*pendingRequest, error) {
+    entry, err := s.Get(ctx, pendingKey(svc, id))
+    if err != nil || entry == nil {
+        return nil, err
+    }
+    p := &pendingRequest{}
+    if err := entry.DecodeJSON(p); err != nil {
+        return nil, err
+    }
+    return p, nil
+}
+
+func (b *Backend) storePendingRequest(ctx context.Context, s logical.Storage, p *pendingRequest) error {
+    entry, err := logical.StorageEntryJSON(pendingKey(p.ServiceName, p.ID), p)
+    if err != nil {
+        return err
+    }
+    if err := s.Put(ctx, entry); err != nil {
+        b.Logger().Error("Failed to store pending request", "name", p.ServiceName, "id", p.ID, "error", err)
+        return err
+    }
+    return nil
+}
@@ -0,0 +1,195 @@
+// internal/usecase/audit.go
+package usecase
+
+import (
+    "context"
+    "crypto/ed25519"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "sort"
+
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+    auditKeyPath     = "audit/key"
+    auditHeadsPrefix = "audit/heads/"
+)
+
+// auditKeyEntry is the stored mount-level Ed25519 key signing the history chain heads.
+type auditKeyEntry struct {
+    Seed []byte `json:"seed"`
+}
+
+// chainHead is the last record of the history chain of a key‑manager, signed by the audit key.
+type chainHead struct {
+    ServiceName string `json:"service_name"`
+    Seq         uint64 `json:"seq"`
+    ID          string `json:"id"`
+    RecordHash  string `json:"record_hash"`
+    Signature   string `json:"signature"` // hex Ed25519 over message()
+}
+
+// message is what the audit key signs for a head.
+func (h *chainHead) message() []byte {
+    return []byte(fmt.Sprintf("vault-ton-signer history head\n%s\n%d\n%s", h.ServiceName, h.Seq, h.RecordHash))
+}
+
+// recordHash is the hex SHA-256 of the JSON encoding of the entry with an empty record_hash.
+func (e *historyEntry) recordHash() (string, error) {
+    c := *e
+    c.RecordHash = ""
+    raw, err := json.Marshal(&c)
+    if err != nil {
+        return "", err
+    }
+    sum := sha256.Sum256(raw)
+    return hex.EncodeToString(sum[:]), nil
+}
+
+// auditKey loads the mount audit key, generating it on first use.
+func (b *Backend) auditKey(ctx context.Context, s logical.Storage) (ed25519.PrivateKey, error) {
+    b.auditKeyLock.Lock()
+    defer b.auditKeyLock.Unlock()
+
+    entry, err := s.Get(ctx, auditKeyPath)
+    if err != nil {
+        return nil, err
+    }
+    if entry != nil {
+        var stored auditKeyEntry
+        if err := entry.DecodeJSON(&stored); err != nil {
+            return nil, err
+        }
+        if len(stored.Seed) != ed25519.SeedSize {
+            return nil, fmt.Errorf("invalid stored audit key")
+        }
+        return ed25519.NewKeyFromSeed(stored.Seed), nil
+    }
+
+    _, priv, err := ed25519.GenerateKey(rand.Reader)
+    if err != nil {
+        return nil, fmt.Errorf("failed to generate audit key: %w", err)
+    }
+    entry, err = logical.StorageEntryJSON(auditKeyPath, auditKeyEntry{Seed: priv.Seed()})
+    if err != nil {
+        return nil, err
+    }
+    if err := s.Put(ctx, entry); err != nil {
+        b.Logger().Error("Failed to store audit key", "error", err)
+        return nil, err
+    }
+    b.Logger().Info("Generated mount audit key")
+    return priv, nil
+}
+
+func (b *Backend) chainHead(ctx context.Context, s logical.Storage, svc string) (*chainHead, error) {
+    entry, err := s.Get(ctx, auditHeadsPrefix+svc)
+    if err != nil || entry == nil {
+        return nil, err
+    }
+    h := &chainHead{}
+    if err := entry.DecodeJSON(h); err != nil {
+        return nil, err
+    }
+    return h, nil
+}
+
+// storeChainHead signs and stores the head of the history chain of svc.
+func (b *Backend) storeChainHead(ctx context.Context, s logical.Storage, e *historyEntry) error {
+    key, err := b.auditKey(ctx, s)
+    if err != nil {
+        return err
+    }
+    h := &chainHead{ServiceName: e.ServiceName, Seq: e.Seq, ID: e.ID, RecordHash: e.RecordHash}
+    h.Signature = hex.EncodeToString(ed25519.Sign(key, h.message()))
+    entry, err := logical.StorageEntryJSON(auditHeadsPrefix+e.ServiceName, h)
+    if err != nil {
+        return err
+    }
+    return s.Put(ctx, entry)
+}
+
+// chainBreak describes the first broken link of a chain.
+type chainBreak struct {
+    ID     string `json:"id"`
+    Reason string `json:"reason"`
+}
+
+// verifyChain walks the history of svc from its oldest kept entry to the signed head.
+// Entries deleted by retention are not checked: the oldest kept entry anchors the chain.
+// Records are walked in chain order: ids follow the wall clock, which may step back.
+func (b *Backend) verifyChain(ctx context.Context, s logical.Storage, svc string, pub ed25519.PublicKey) (int, *chainBreak, error) {
+    prefix := historyPrefix + svc + "/"
+    ids, err := s.List(ctx, prefix)
+    if err != nil {
+        return 0, nil, err
+    }
+    sort.Strings(ids)
+
+    type record struct {
+        id string
+        e  *historyEntry
+    }
+    records := make([]record, 0, len(ids))
+    for _, id := range ids {
+        entry, err := s.Get(ctx, prefix+id)
+        if err != nil {
+            return 0, nil, err
+        }
+        if entry == nil {
+            continue
+        }
+        e := &historyEntry{}
+        if err := entry.DecodeJSON(e); err != nil {
+            return 0, &chainBreak{ID: id, Reason: "record cannot be decoded"}, nil
+        }
+        records = append(records, record{id: id, e: e})
+    }
+    sort.SliceStable(records, func(i, j int) bool { return records[i].e.Seq < records[j].e.Seq })
+
+    var prev *historyEntry
+    for _, r := range records {
+        id, e := r.id, r.e
+        hash, err := e.recordHash()
+        if err != nil {
+            return 0, nil, err
+        }
+        switch {
+        case e.ID != id:
+            return 0, &chainBreak{ID: id, Reason: "record id does not match its storage key"}, nil
+        case hash != e.RecordHash:
+            return 0, &chainBreak{ID: id, Reason: "record was modified: hash mismatch"}, nil
+        case prev != nil && e.Seq != prev.Seq+1:
+            return 0, &chainBreak{ID: id, Reason: fmt.Sprintf("sequence jumps from %d to %d: records are missing", prev.Seq, e.Seq)}, nil
+        case prev != nil && e.PrevHash != prev.RecordHash:
+            return 0, &chainBreak{ID: id, Reason: "prev_hash does not match the previous record"}, nil
+        }
+        prev = e
+    }
+
+    head, err := b.chainHead(ctx, s, svc)
+    if err != nil {
+        return 0, nil, err
+    }
+    n := len(ids)
+    switch {
+    case head == nil && prev == nil:
+        return 0, nil, nil
+    case head == nil:
+        return n, &chainBreak{ID: prev.ID, Reason: "chain has no signed head"}, nil
+    case prev == nil:
+        return 0, &chainBreak{ID: head.ID, Reason: "signed head has no records"}, nil
+    }
+    sig, err := hex.DecodeString(head.Signature)
+    if err != nil || !ed25519.Verify(pub, head.message(), sig) {
+        return n, &chainBreak{ID: head.ID, Reason: "head signature is invalid"}, nil
+    }
+    if head.ID != prev.ID || head.Seq != prev.Seq || head.RecordHash != prev.RecordHash {
+        return n, &chainBreak{ID: prev.ID, Reason: fmt.Sprintf("last record does not match the signed head %s", head.ID)}, nil
+    }
+    return n, nil, nil
+}
@@ -4,12 +4,33 @@ package usecase
 import (
     "context"
     "fmt"
+    "sync"
 
     "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/helper/locksutil"
     "github.com/hashicorp/vault/sdk/logical"
 )
 
-type Backend struct{ *framework.Backend }
+type Backend struct {
+    *framework.Backend
+
+    // wrappingKeyLock serializes generation of the mount wrapping key.
+    wrappingKeyLock sync.Mutex
+    // auditKeyLock serializes generation of the mount audit key.
+    auditKeyLock sync.Mutex
+    // spendLocks serialize limit checks and spend records per key‑manager.
+    spendLocks []*locksutil.LockEntry
+    // pendingLocks serialize approvals of a pending request.
+    pendingLocks []*locksutil.LockEntry
+    // historyLocks serialize appends to the history chain of a key‑manager.
+    historyLocks []*locksutil.LockEntry
+    // idempotencyLocks serialize the requests sharing an idempotency key.
+    idempotencyLocks []*locksutil.LockEntry
+    // indexLocks serialize updates of a reverse index entry.
+    indexLocks []*locksutil.LockEntry
+    // rateBuckets hold the rate limit token buckets of all key‑managers.
+    rateBuckets *rateBuckets
+}
 
 func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
     b := backend()
@@ -20,16 +41,34 @@ func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend,
 }
 
 func backend() *Backend {
-    b := &Backend{}
+    b := &Backend{
+        spendLocks:       locksutil.CreateLocks(),
+        pendingLocks:     locksutil.CreateLocks(),
+        historyLocks:     locksutil.CreateLocks(),
+        idempotencyLocks: locksutil.CreateLocks(),
+        indexLocks:       locksutil.CreateLocks(),
+        rateBuckets:      newRateBuckets(),
+    }
     b.Backend = &framework.Backend{
         Help: "Vault TON Signer plugin",
         Paths: framework.PathAppend(
             paths(b),
+            pathLookup(b),
+            pathBackup(b),
+            pathEscrow(b),
+            pathApprovals(b),
+            pathW5Extensions(b),
+            pathMultisig(b),
+            []*framework.Path{pathHistory(b)},
+            pathAudit(b),
+            []*framework.Path{pathWrappingKey(b)},
         ),
         PathsSpecial: &logical.Paths{
-            SealWrapStorage: []string{"key-managers/"},
+            SealWrapStorage: []string{"key-managers/", wrappingKeyPath, auditKeyPath},
         },
-        BackendType: logical.TypeLogical,
+        BackendType:    logical.TypeLogical,
+        InitializeFunc: b.initialize,
+        PeriodicFunc:   b.periodic,
     }
     return b
 }
@@ -42,3 +81,11 @@ func (b *Backend) pathExistenceCheck(ctx context.Context, req *logical.Request,
     }
     return entry != nil, nil
 }
+
+// periodic tidies the history past its retention and the expired idempotency records.
+func (b *Backend) periodic(ctx context.Context, req *logical.Request) error {
+    if err := b.tidyHistory(ctx, req); err != nil {
+        return err
+    }
+    return b.tidyIdempotency(ctx, req)
+}
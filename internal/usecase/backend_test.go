@@ -4,7 +4,6 @@ package usecase
 
 import (
     "context"
-    "crypto/sha256"
     "encoding/base64"
     "crypto/ed25519"
     "encoding/hex"
@@ -13,6 +12,10 @@ import (
     "github.com/hashicorp/vault/sdk/logical"
     "github.com/stretchr/testify/assert"
     "github.com/stretchr/testify/require"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/wallet"
 )
 
 // newTestBackend возвращает Backend с in-memory сториджем для тестов.
@@ -32,6 +35,25 @@ func newTestBackend(t *testing.T) (*Backend, logical.Storage) {
     return be, storage
 }
 
+// doRequest sends a request as entity to b and fails the test on a transport error.
+func doRequest(t *testing.T, b *Backend, storage logical.Storage, op logical.Operation, path, entity string, data map[string]interface{}) *logical.Response {
+    t.Helper()
+    req := logical.TestRequest(t, op, path)
+    req.Storage = storage
+    req.EntityID = entity
+    req.Data = data
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    return resp
+}
+
+// requireOK fails the test unless resp is a successful response.
+func requireOK(t *testing.T, resp *logical.Response) *logical.Response {
+    t.Helper()
+    require.False(t, resp != nil && resp.IsError(), "%v", resp)
+    return resp
+}
+
 func TestCreateAndListKeyManagers(t *testing.T) {
     b, storage := newTestBackend(t)
 
@@ -104,29 +126,38 @@ func TestTransferTon(t *testing.T) {
     // Create manager
     req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
     req.Storage = storage
-    req.Data = map[string]interface{}{"serviceName": "svc"}
+    req.Data = map[string]interface{}{
+        "serviceName": "svc",
+        "privateKey":  testSeed,
+    }
     resp, err := b.HandleRequest(context.Background(), req)
     require.NoError(t, err)
     addr := resp.Data["address"].(string)
     require.NotEmpty(t, addr)
+    pub, err := hex.DecodeString(resp.Data["public_key"].(string))
+    require.NoError(t, err)
 
-    // Dummy TON transfer: returns seed as BOC
+    // TON transfer: returns a signed external message
     req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/txn/ton/transfer")
     req.Storage = storage
-    req.Data = map[string]interface{}{"name": "svc"}
+    req.Data = map[string]interface{}{
+        "name":        "svc",
+        "destination": testDestination,
+        "amount":      "1500000000",
+        "comment":     "hello",
+        "seqno":       3,
+    }
     resp, err = b.HandleRequest(context.Background(), req)
     require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
 
-    b64 := resp.Data["signed_boc"].(string)
-    bocBytes, err := base64.StdEncoding.DecodeString(b64)
+    msgs, bocBytes := decodeSignedTransfer(t, resp, pub)
+    assert.NotContains(t, hex.EncodeToString(bocBytes), testSeed)
+    require.Len(t, msgs, 1)
+    dest, err := ton.AccountIDFromTlb(msgs[0].Info.IntMsgInfo.Dest)
     require.NoError(t, err)
-    require.NotEmpty(t, bocBytes)
-
-    // Compute expected msg_id
-    sum := sha256.Sum256(bocBytes)
-    wantID := hex.EncodeToString(sum[:])
-    gotID := resp.Data["msg_id"].(string)
-    assert.Equal(t, wantID, gotID)
+    assert.Equal(t, testDestination, dest.ToRaw())
+    assert.Equal(t, tlb.Grams(1500000000), msgs[0].Info.IntMsgInfo.Value.Grams)
 }
 
 func TestTransferJetton(t *testing.T) {
@@ -138,24 +169,58 @@ func TestTransferJetton(t *testing.T) {
     req.Data = map[string]interface{}{"serviceName": "svc"}
     resp, err := b.HandleRequest(context.Background(), req)
     require.NoError(t, err)
+    pub, err := hex.DecodeString(resp.Data["public_key"].(string))
+    require.NoError(t, err)
 
-    // Dummy Jetton transfer: returns seed as BOC
+    // Jetton transfer: returns a signed external message to the jetton wallet
     req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/txn/jetton/transfer")
     req.Storage = storage
     req.Data = map[string]interface{}{
         "name":         "svc",
         "jettonWallet": resp.Data["address"].(string),
+        "destination":  testDestination,
+        "amount":       "1000000",
     }
     resp, err = b.HandleRequest(context.Background(), req)
     require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
 
-    b64 := resp.Data["signed_boc"].(string)
-    bocBytes, err := base64.StdEncoding.DecodeString(b64)
+    msgs, _ := decodeSignedTransfer(t, resp, pub)
+    require.Len(t, msgs, 1)
+    body := boc.Cell(msgs[0].Body.Value)
+    op, err := body.ReadUint(32)
     require.NoError(t, err)
-    require.NotEmpty(t, bocBytes)
+    assert.Equal(t, uint64(opJettonTransfer), op)
+}
 
-    sum := sha256.Sum256(bocBytes)
-    wantID := hex.EncodeToString(sum[:])
-    gotID := resp.Data["msg_id"].(string)
-    assert.Equal(t, wantID, gotID)
+const (
+    testSeed        = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+    testDestination = "0:1111111111111111111111111111111111111111111111111111111111111111"
+)
+
+// decodeSignedTransfer checks msg_id and the wallet signature of a signed_boc
+// and returns the internal messages it carries.
+func decodeSignedTransfer(t *testing.T, resp *logical.Response, pub ed25519.PublicKey) ([]tlb.Message, []byte) {
+    t.Helper()
+    bocBytes, err := base64.StdEncoding.DecodeString(resp.Data["signed_boc"].(string))
+    require.NoError(t, err)
+    cells, err := boc.DeserializeBoc(bocBytes)
+    require.NoError(t, err)
+    require.Len(t, cells, 1)
+
+    hash, err := cells[0].Hash256()
+    require.NoError(t, err)
+    assert.Equal(t, hex.EncodeToString(hash[:]), resp.Data["msg_id"])
+
+    cells[0].ResetCounters()
+    require.NoError(t, wallet.VerifySignature(wallet.V4R2, cells[0], pub))
+
+    cells[0].ResetCounters()
+    raw, err := wallet.ExtractRawMessages(wallet.V4R2, cells[0])
+    require.NoError(t, err)
+    msgs := make([]tlb.Message, len(raw))
+    for i, m := range raw {
+        require.NoError(t, tlb.Unmarshal(m.Message, &msgs[i]))
+    }
+    return msgs, bocBytes
 }
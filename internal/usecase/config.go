@@ -0,0 +1,139 @@
+// internal/usecase/config.go
+package usecase
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+const (
+    configPath = "config"
+
+    networkMainnet = "mainnet"
+    networkTestnet = "testnet"
+)
+
+// backendConfig holds the mount-level defaults and limits.
+type backendConfig struct {
+    WalletVersion     string `json:"wallet_version"`
+    Network           string `json:"network"`
+    Workchain         int    `json:"workchain"`
+    MaxBatchSize      int    `json:"max_batch_size"`       // 0: as many as the wallet accepts
+    MaxKeyManagers    int    `json:"max_key_managers"`     // 0: unlimited
+    MaxKeysPerManager int    `json:"max_keys_per_manager"` // 0: unlimited
+
+    HistoryRetention time.Duration `json:"history_retention"` // 0: keep forever
+    IdempotencyTTL   time.Duration `json:"idempotency_ttl"`
+
+    DefaultMessageLifetime time.Duration `json:"default_message_lifetime"`
+    MaxMessageLifetime     time.Duration `json:"max_message_lifetime"` // 0: unlimited
+}
+
+func defaultConfig() *backendConfig {
+    return &backendConfig{
+        WalletVersion:  defaultWalletVersion.ToString(),
+        Network:        networkMainnet,
+        IdempotencyTTL: defaultIdempotencyTTL,
+
+        DefaultMessageLifetime: wallet.DefaultMessageLifetime,
+    }
+}
+
+// walletSettings selects the wallet contract of a key pair.
+type walletSettings struct {
+    Version   wallet.Version
+    Workchain int
+    Network   string
+}
+
+// walletSettings returns the settings new keys get unless a request overrides them.
+func (c *backendConfig) walletSettings() (walletSettings, error) {
+    ver, err := wallet.VersionFromString(c.WalletVersion)
+    if err != nil {
+        return walletSettings{}, fmt.Errorf("invalid configured wallet_version %q", c.WalletVersion)
+    }
+    return walletSettings{Version: ver, Workchain: c.Workchain, Network: c.Network}, nil
+}
+
+// walletSettingsFromRequest applies the wallet_version, network and workchain
+// overrides of a request to the configured defaults.
+func (c *backendConfig) walletSettingsFromRequest(data *framework.FieldData) (walletSettings, error) {
+    ws, err := c.walletSettings()
+    if err != nil {
+        return ws, err
+    }
+    if v, ok := data.GetOk("wallet_version"); ok {
+        if ws.Version, err = wallet.VersionFromString(v.(string)); err != nil {
+            return ws, fmt.Errorf("invalid wallet_version %q", v)
+        }
+    }
+    if v, ok := data.GetOk("network"); ok {
+        ws.Network = v.(string)
+    }
+    if v, ok := data.GetOk("workchain"); ok {
+        ws.Workchain = v.(int)
+    }
+    return ws, ws.validate()
+}
+
+// validate checks the settings are usable for signing.
+func (ws walletSettings) validate() error {
+    if _, ok := maxWalletMessages[ws.Version]; !ok {
+        return fmt.Errorf("unsupported wallet_version %q", ws.Version.ToString())
+    }
+    if ws.Workchain != 0 && ws.Workchain != -1 {
+        return fmt.Errorf("workchain must be 0 or -1, got %d", ws.Workchain)
+    }
+    if ws.Network != networkMainnet && ws.Network != networkTestnet {
+        return fmt.Errorf("network must be %q or %q, got %q", networkMainnet, networkTestnet, ws.Network)
+    }
+    return nil
+}
+
+// networkGlobalID returns the global id of the network, part of the V5 wallet id.
+func networkGlobalID(network string) int32 {
+    if network == networkTestnet {
+        return wallet.TestnetGlobalID
+    }
+    return wallet.MainnetGlobalID
+}
+
+// config loads the mount configuration, filling in defaults.
+func (b *Backend) config(ctx context.Context, s logical.Storage) (*backendConfig, error) {
+    cfg := defaultConfig()
+    entry, err := s.Get(ctx, configPath)
+    if err != nil {
+        return nil, err
+    }
+    if entry == nil {
+        return cfg, nil
+    }
+    if err := entry.DecodeJSON(cfg); err != nil {
+        return nil, err
+    }
+    return cfg, nil
+}
+
+// checkKeyLimits enforces the configured key-count limits before a key is added to km.
+// isNew reports whether km is not stored yet.
+func (b *Backend) checkKeyLimits(ctx context.Context, req *logical.Request, cfg *backendConfig, km *KeyManager, isNew bool) error {
+    if cfg.MaxKeysPerManager > 0 && len(km.KeyPairs) >= cfg.MaxKeysPerManager {
+        return fmt.Errorf("key-manager %q already holds the maximum of %d keys", km.ServiceName, cfg.MaxKeysPerManager)
+    }
+    if !isNew || cfg.MaxKeyManagers <= 0 {
+        return nil
+    }
+    services, err := req.Storage.List(ctx, "key-managers/")
+    if err != nil {
+        return err
+    }
+    if len(services) >= cfg.MaxKeyManagers {
+        return fmt.Errorf("mount already holds the maximum of %d key-managers", cfg.MaxKeyManagers)
+    }
+    return nil
+}
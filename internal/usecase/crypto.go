@@ -0,0 +1,246 @@
+// internal/usecase/crypto.go
+package usecase
+
+import (
+    "crypto/aes"
+    "crypto/cipher"
+    "crypto/ecdh"
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/sha256"
+    "crypto/x509"
+    "encoding/base64"
+    "encoding/hex"
+    "encoding/pem"
+    "fmt"
+    "strings"
+
+    "github.com/google/tink/go/kwp/subtle"
+    "golang.org/x/crypto/argon2"
+    "golang.org/x/crypto/nacl/box"
+)
+
+const (
+    // encAlgRSA is RSA-OAEP(SHA-256) of a random AES-256 key followed by
+    // nonce(12) || AES-256-GCM(plaintext) under that key.
+    encAlgRSA = "rsa-oaep-sha256+aes-256-gcm"
+    // encAlgX25519 is a NaCl/libsodium sealed box (crypto_box_seal).
+    encAlgX25519 = "x25519-sealed-box"
+
+    // encAlgPassphrase is AES-256-GCM under an Argon2id key derived from a passphrase.
+    encAlgPassphrase = "argon2id+aes-256-gcm"
+
+    minRSABits = 2048
+)
+
+// argon2Params are the Argon2id parameters of a passphrase-derived key.
+type argon2Params struct {
+    Salt    []byte `json:"salt"`
+    Time    uint32 `json:"time"`
+    Memory  uint32 `json:"memory"` // KiB
+    Threads uint8  `json:"threads"`
+}
+
+// newArgon2Params returns the RFC 9106 second recommended parameters with a fresh salt.
+func newArgon2Params() (*argon2Params, error) {
+    salt := make([]byte, 16)
+    if _, err := rand.Read(salt); err != nil {
+        return nil, err
+    }
+    return &argon2Params{Salt: salt, Time: 3, Memory: 64 * 1024, Threads: 4}, nil
+}
+
+// Bounds on the Argon2id parameters of a backup, which come from the backup itself:
+// a restore must not panic or spend unbounded time and memory deriving its key.
+const (
+    argon2MinSalt    = 8
+    argon2MaxTime    = 16
+    argon2MaxMemory  = 1 << 20 // KiB, 1 GiB
+    argon2MaxThreads = 64
+)
+
+// validate checks the parameters against the server-side bounds.
+func (p *argon2Params) validate() error {
+    switch {
+    case len(p.Salt) < argon2MinSalt:
+        return fmt.Errorf("kdf salt must be at least %d bytes", argon2MinSalt)
+    case p.Time < 1 || p.Time > argon2MaxTime:
+        return fmt.Errorf("kdf time must be between 1 and %d", argon2MaxTime)
+    case p.Threads < 1 || p.Threads > argon2MaxThreads:
+        return fmt.Errorf("kdf threads must be between 1 and %d", argon2MaxThreads)
+    case p.Memory < 8*uint32(p.Threads) || p.Memory > argon2MaxMemory:
+        return fmt.Errorf("kdf memory must be between 8 KiB per thread and %d KiB", argon2MaxMemory)
+    }
+    return nil
+}
+
+func (p *argon2Params) key(passphrase string) []byte {
+    return argon2.IDKey([]byte(passphrase), p.Salt, p.Time, p.Memory, p.Threads, 32)
+}
+
+// encryptToRecipient encrypts plaintext to a caller-supplied public key: a PEM
+// RSA or X25519 key, or a raw X25519 key in hex or base64.
+func encryptToRecipient(publicKey string, plaintext []byte) (alg string, ciphertext []byte, err error) {
+    key, err := parseRecipientKey(publicKey)
+    if err != nil {
+        return "", nil, err
+    }
+    ciphertext, err = sealToKey(key, plaintext)
+    return recipientAlgorithm(key), ciphertext, err
+}
+
+// recipientAlgorithm names the scheme sealToKey uses for a parsed recipient key.
+func recipientAlgorithm(key interface{}) string {
+    if _, ok := key.(*rsa.PublicKey); ok {
+        return encAlgRSA
+    }
+    return encAlgX25519
+}
+
+// sealToKey encrypts plaintext to a key returned by parseRecipientKey.
+func sealToKey(key interface{}, plaintext []byte) ([]byte, error) {
+    switch k := key.(type) {
+    case *rsa.PublicKey:
+        return encryptRSAHybrid(k, plaintext)
+    case *ecdh.PublicKey:
+        var recipient [32]byte
+        copy(recipient[:], k.Bytes())
+        return box.SealAnonymous(nil, plaintext, &recipient, rand.Reader)
+    default:
+        return nil, fmt.Errorf("unsupported public key type %T", key)
+    }
+}
+
+func parseRecipientKey(publicKey string) (interface{}, error) {
+    publicKey = strings.TrimSpace(publicKey)
+    if publicKey == "" {
+        return nil, fmt.Errorf("public_key is required")
+    }
+    if strings.HasPrefix(publicKey, "-----BEGIN") {
+        block, _ := pem.Decode([]byte(publicKey))
+        if block == nil {
+            return nil, fmt.Errorf("public_key is not valid PEM")
+        }
+        key, err := x509.ParsePKIXPublicKey(block.Bytes)
+        if err != nil {
+            if rsaKey, rsaErr := x509.ParsePKCS1PublicKey(block.Bytes); rsaErr == nil {
+                key = rsaKey
+            } else {
+                return nil, fmt.Errorf("failed to parse public_key: %w", err)
+            }
+        }
+        switch k := key.(type) {
+        case *rsa.PublicKey:
+            if k.N.BitLen() < minRSABits {
+                return nil, fmt.Errorf("RSA public_key must be at least %d bits", minRSABits)
+            }
+            return k, nil
+        case *ecdh.PublicKey:
+            if k.Curve() != ecdh.X25519() {
+                return nil, fmt.Errorf("only X25519 ECDH public keys are supported")
+            }
+            return k, nil
+        default:
+            return nil, fmt.Errorf("public_key must be an RSA or X25519 key, got %T", key)
+        }
+    }
+
+    raw, err := hex.DecodeString(publicKey)
+    if err != nil {
+        if raw, err = base64.StdEncoding.DecodeString(publicKey); err != nil {
+            return nil, fmt.Errorf("public_key must be PEM, or a hex/base64 X25519 key")
+        }
+    }
+    return ecdh.X25519().NewPublicKey(raw)
+}
+
+func encryptRSAHybrid(pub *rsa.PublicKey, plaintext []byte) ([]byte, error) {
+    key := make([]byte, 32)
+    if _, err := rand.Read(key); err != nil {
+        return nil, err
+    }
+    defer zeroSeed(key)
+
+    wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, key, nil)
+    if err != nil {
+        return nil, fmt.Errorf("failed to wrap key: %w", err)
+    }
+    sealed, err := sealAESGCM(key, plaintext, nil)
+    if err != nil {
+        return nil, err
+    }
+    return append(wrapped, sealed...), nil
+}
+
+func decryptRSAHybrid(priv *rsa.PrivateKey, ciphertext []byte) ([]byte, error) {
+    n := priv.Size()
+    if len(ciphertext) <= n {
+        return nil, fmt.Errorf("ciphertext is too short")
+    }
+    key, err := rsa.DecryptOAEP(sha256.New(), nil, priv, ciphertext[:n], nil)
+    if err != nil {
+        return nil, fmt.Errorf("failed to unwrap key: %w", err)
+    }
+    defer zeroSeed(key)
+    return openAESGCM(key, ciphertext[n:], nil)
+}
+
+// sealAESGCM returns nonce || AES-256-GCM(plaintext).
+func sealAESGCM(key, plaintext, additionalData []byte) ([]byte, error) {
+    block, err := aes.NewCipher(key)
+    if err != nil {
+        return nil, err
+    }
+    gcm, err := cipher.NewGCM(block)
+    if err != nil {
+        return nil, err
+    }
+    nonce := make([]byte, gcm.NonceSize())
+    if _, err := rand.Read(nonce); err != nil {
+        return nil, err
+    }
+    return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// openAESGCM reverses sealAESGCM.
+func openAESGCM(key, sealed, additionalData []byte) ([]byte, error) {
+    block, err := aes.NewCipher(key)
+    if err != nil {
+        return nil, err
+    }
+    gcm, err := cipher.NewGCM(block)
+    if err != nil {
+        return nil, err
+    }
+    if len(sealed) < gcm.NonceSize() {
+        return nil, fmt.Errorf("ciphertext is too short")
+    }
+    plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], additionalData)
+    if err != nil {
+        return nil, fmt.Errorf("decryption failed: wrong key or corrupted data")
+    }
+    return plaintext, nil
+}
+
+// unwrapRSAKWP reverses the BYOK wrapping: RSA-OAEP(SHA-256) of an AES-256 key
+// followed by the AES-KWP wrapped payload.
+func unwrapRSAKWP(priv *rsa.PrivateKey, wrapped []byte) ([]byte, error) {
+    n := priv.Size()
+    if len(wrapped) <= n {
+        return nil, fmt.Errorf("wrapped key is too short")
+    }
+    key, err := rsa.DecryptOAEP(sha256.New(), nil, priv, wrapped[:n], nil)
+    if err != nil {
+        return nil, fmt.Errorf("failed to unwrap ephemeral key: %w", err)
+    }
+    defer zeroSeed(key)
+    kwp, err := subtle.NewKWP(key)
+    if err != nil {
+        return nil, err
+    }
+    plaintext, err := kwp.Unwrap(wrapped[n:])
+    if err != nil {
+        return nil, fmt.Errorf("failed to unwrap key: %w", err)
+    }
+    return plaintext, nil
+}
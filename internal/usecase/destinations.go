@@ -0,0 +1,60 @@
+// internal/usecase/destinations.go
+package usecase
+
+import (
+    "fmt"
+
+    "github.com/tonkeeper/tongo/ton"
+)
+
+// checkDestinations refuses messages sending to a denied address, or to an
+// address missing from a non-empty allowlist. The address a message is sent to,
+// the final recipient of jetton and NFT transfers and the address receiving
+// their excess are checked.
+func (km *KeyManager) checkDestinations(msgs []*outMessage) error {
+    if len(km.AllowedDestinations) == 0 && len(km.DeniedDestinations) == 0 {
+        return nil
+    }
+    for i, m := range msgs {
+        addrs := []ton.AccountID{m.Destination}
+        if m.Recipient != nil {
+            addrs = append(addrs, *m.Recipient)
+        }
+        if m.ResponseDestination != nil {
+            addrs = append(addrs, *m.ResponseDestination)
+        }
+        for _, addr := range addrs {
+            if err := km.checkDestination(addr); err != nil {
+                return fmt.Errorf("message %d: %w", i+1, err)
+            }
+        }
+    }
+    return nil
+}
+
+// checkDestination refuses a denied address, or one missing from a non-empty allowlist.
+func (km *KeyManager) checkDestination(addr ton.AccountID) error {
+    raw := addr.ToRaw()
+    if containsString(km.DeniedDestinations, raw) {
+        return fmt.Errorf("destination %s is denied for key-manager %q", raw, km.ServiceName)
+    }
+    if len(km.AllowedDestinations) > 0 && !containsString(km.AllowedDestinations, raw) {
+        return fmt.Errorf("destination %s is not allowed for key-manager %q", raw, km.ServiceName)
+    }
+    return nil
+}
+
+// normalizeAddresses returns the raw form of each address, dropping duplicates.
+func normalizeAddresses(addrs []string) ([]string, error) {
+    out := make([]string, 0, len(addrs))
+    for _, a := range addrs {
+        raw, err := normalizeAddress(a)
+        if err != nil {
+            return nil, err
+        }
+        if !containsString(out, raw) {
+            out = append(out, raw)
+        }
+    }
+    return out, nil
+}
@@ -0,0 +1,155 @@
+// internal/usecase/escrow.go
+package usecase
+
+import (
+    "crypto/ed25519"
+    "encoding/base64"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+
+    "github.com/hashicorp/vault/sdk/framework"
+
+    "github.com/dsshard/vault-ton-signer/internal/shamir"
+)
+
+// escrowShare is the plaintext each officer decrypts from their escrow share.
+// The recovery endpoint takes these documents back.
+type escrowShare struct {
+    ServiceName   string `json:"service_name"`
+    KeyVersion    int    `json:"key_version"`
+    PublicKey     string `json:"public_key"`
+    WalletVersion string `json:"wallet_version"`
+    Workchain     int    `json:"workchain"`
+    Network       string `json:"network"`
+    Threshold     int    `json:"threshold"`
+    Share         string `json:"share"` // hex
+}
+
+// walletSettings returns the wallet contract recorded in the share, or the
+// configured one for shares that do not record it.
+func (s *escrowShare) walletSettings(cfg *backendConfig) (walletSettings, error) {
+    if s.WalletVersion == "" {
+        return cfg.walletSettings()
+    }
+    kp := &KeyPair{ID: s.KeyVersion, WalletVersion: s.WalletVersion, Workchain: s.Workchain, Network: s.Network}
+    ws, err := kp.walletSettings()
+    if err != nil {
+        return ws, err
+    }
+    return ws, ws.validate()
+}
+
+// escrowFields returns the schema of the escrow parameters.
+func escrowFields() map[string]*framework.FieldSchema {
+    return map[string]*framework.FieldSchema{
+        "escrow_public_keys": {
+            Type:        framework.TypeStringSlice,
+            Description: "(Optional) One public key per officer (PEM RSA or X25519, or raw X25519 hex/base64). The seed is split into one Shamir share per key.",
+        },
+        "escrow_threshold": {
+            Type:        framework.TypeInt,
+            Description: "(Optional) Number of shares needed to recover the seed. Between 2 and the number of escrow_public_keys.",
+        },
+    }
+}
+
+// escrowSeed splits the seed of kp with Shamir's secret sharing and encrypts
+// share i to publicKeys[i]. Nothing is stored.
+func escrowSeed(svc string, kp *KeyPair, publicKeys []string, threshold int) ([]map[string]interface{}, error) {
+    if threshold < 2 || threshold > len(publicKeys) {
+        return nil, fmt.Errorf("escrow_threshold must be between 2 and the number of escrow_public_keys (%d)", len(publicKeys))
+    }
+    recipients := make([]interface{}, len(publicKeys))
+    for i, pk := range publicKeys {
+        key, err := parseRecipientKey(pk)
+        if err != nil {
+            return nil, fmt.Errorf("escrow_public_keys[%d]: %w", i, err)
+        }
+        recipients[i] = key
+    }
+
+    seed, err := hex.DecodeString(kp.PrivateKey)
+    if err != nil {
+        return nil, fmt.Errorf("invalid stored seed hex: %w", err)
+    }
+    defer zeroSeed(seed)
+    shares, err := shamir.Split(seed, len(publicKeys), threshold)
+    if err != nil {
+        return nil, err
+    }
+
+    out := make([]map[string]interface{}, len(shares))
+    for i, share := range shares {
+        plaintext, err := json.Marshal(escrowShare{
+            ServiceName:   svc,
+            KeyVersion:    kp.ID,
+            PublicKey:     kp.PublicKey,
+            WalletVersion: kp.WalletVersion,
+            Workchain:     kp.Workchain,
+            Network:       kp.Network,
+            Threshold:     threshold,
+            Share:         hex.EncodeToString(share),
+        })
+        zeroSeed(share)
+        if err != nil {
+            return nil, err
+        }
+        ciphertext, err := sealToKey(recipients[i], plaintext)
+        zeroSeed(plaintext)
+        if err != nil {
+            return nil, fmt.Errorf("failed to encrypt escrow share %d: %w", i+1, err)
+        }
+        out[i] = map[string]interface{}{
+            "index":      i + 1,
+            "algorithm":  recipientAlgorithm(recipients[i]),
+            "ciphertext": base64.StdEncoding.EncodeToString(ciphertext),
+        }
+    }
+    return out, nil
+}
+
+// combineEscrowShares rebuilds a seed from decrypted escrow shares and checks
+// it against the public key recorded in the shares.
+func combineEscrowShares(docs []string) ([]byte, *escrowShare, error) {
+    if len(docs) < 2 {
+        return nil, nil, fmt.Errorf("at least 2 shares are required")
+    }
+    var first *escrowShare
+    parts := make([][]byte, len(docs))
+    defer func() {
+        for _, p := range parts {
+            zeroSeed(p)
+        }
+    }()
+    for i, doc := range docs {
+        var s escrowShare
+        if err := json.Unmarshal([]byte(doc), &s); err != nil {
+            return nil, nil, fmt.Errorf("share %d is not a decrypted escrow share: %w", i+1, err)
+        }
+        if first == nil {
+            first = &s
+        } else if s.PublicKey != first.PublicKey || s.KeyVersion != first.KeyVersion {
+            return nil, nil, fmt.Errorf("share %d belongs to a different key", i+1)
+        }
+        part, err := hex.DecodeString(s.Share)
+        if err != nil {
+            return nil, nil, fmt.Errorf("share %d has invalid share hex: %w", i+1, err)
+        }
+        parts[i] = part
+    }
+    if len(docs) < first.Threshold {
+        return nil, nil, fmt.Errorf("%d shares are required, got %d", first.Threshold, len(docs))
+    }
+
+    seed, err := shamir.Combine(parts)
+    if err != nil {
+        return nil, nil, err
+    }
+    pub := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+    if hex.EncodeToString(pub) != first.PublicKey {
+        zeroSeed(seed)
+        return nil, nil, fmt.Errorf("shares do not recover the escrowed key")
+    }
+    return seed, first, nil
+}
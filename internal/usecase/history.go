@@ -0,0 +1,159 @@
+// internal/usecase/history.go
+package usecase
+
+import (
+    "context"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/go-uuid"
+    "github.com/hashicorp/vault/sdk/helper/locksutil"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+const historyPrefix = "history/"
+
+// historyEntry records one successful sign or txn call. Entries are stored under
+// ids starting with the zero-padded unix nanoseconds of the call, so storage
+// listings are in time order. The entries of a key‑manager form a hash chain whose
+// head is signed by the mount audit key.
+type historyEntry struct {
+    ID          string                   `json:"id"`
+    At          time.Time                `json:"at"`
+    ServiceName string                   `json:"service_name"`
+    KeyID       int                      `json:"key_id"`
+    Address     string                   `json:"address"`
+    Operation   string                   `json:"operation"`
+    Hash        string                   `json:"hash"` // signed hash, or msg_id of a transaction
+    Seqno       *uint32                  `json:"seqno,omitempty"`
+    Messages    []map[string]interface{} `json:"messages,omitempty"`
+    EntityID    string                   `json:"entity_id"`
+    Request     map[string]interface{}   `json:"request"`
+
+    Seq        uint64 `json:"seq"`
+    PrevHash   string `json:"prev_hash"`
+    RecordHash string `json:"record_hash"`
+}
+
+// historyID returns a time-ordered entry id.
+func historyID(at time.Time) (string, error) {
+    suffix, err := uuid.GenerateRandomBytes(4)
+    if err != nil {
+        return "", err
+    }
+    return fmt.Sprintf("%020d-%x", at.UnixNano(), suffix), nil
+}
+
+// historyTime returns the time encoded in an entry id.
+func historyTime(id string) (time.Time, bool) {
+    ns, _, _ := strings.Cut(id, "-")
+    v, err := strconv.ParseInt(ns, 10, 64)
+    if err != nil {
+        return time.Time{}, false
+    }
+    return time.Unix(0, v).UTC(), true
+}
+
+// requestMetadata returns what identifies a request in the history.
+func requestMetadata(req *logical.Request) map[string]interface{} {
+    md := map[string]interface{}{
+        "request_id":            req.ID,
+        "path":                  req.Path,
+        "display_name":          req.DisplayName,
+        "client_token_accessor": req.ClientTokenAccessor,
+    }
+    if req.Connection != nil {
+        md["remote_address"] = req.Connection.RemoteAddr
+    }
+    return md
+}
+
+// recordHistory stores an entry for a signature produced by kp.
+func (b *Backend) recordHistory(ctx context.Context, req *logical.Request, km *KeyManager, kp *KeyPair, e *historyEntry) error {
+    e.At = time.Now().UTC()
+    id, err := historyID(e.At)
+    if err != nil {
+        return err
+    }
+    e.ID = id
+    e.ServiceName = km.ServiceName
+    e.KeyID = kp.ID
+    e.Address = kp.Address
+    e.EntityID = req.EntityID
+    e.Operation = strings.TrimPrefix(req.Path, "key-managers/"+km.ServiceName+"/")
+    e.Request = requestMetadata(req)
+    if p, ok := ctx.Value(approvedRequestKey{}).(*pendingRequest); ok {
+        e.Request["pending_id"] = p.ID
+    }
+
+    lock := locksutil.LockForKey(b.historyLocks, km.ServiceName)
+    lock.Lock()
+    defer lock.Unlock()
+    head, err := b.chainHead(ctx, req.Storage, km.ServiceName)
+    if err != nil {
+        return err
+    }
+    e.Seq = 1
+    if head != nil {
+        e.Seq, e.PrevHash = head.Seq+1, head.RecordHash
+    }
+    if e.RecordHash, err = e.recordHash(); err != nil {
+        return err
+    }
+
+    entry, err := logical.StorageEntryJSON(historyPrefix+km.ServiceName+"/"+id, e)
+    if err != nil {
+        return err
+    }
+    if err := req.Storage.Put(ctx, entry); err != nil {
+        b.Logger().Error("Failed to store history entry", "name", km.ServiceName, "error", err)
+        return err
+    }
+    if err := b.storeChainHead(ctx, req.Storage, e); err != nil {
+        b.Logger().Error("Failed to store history chain head", "name", km.ServiceName, "error", err)
+        return err
+    }
+    return nil
+}
+
+// tidyHistory deletes the history entries older than the configured retention.
+// It runs as the periodic function of the backend.
+func (b *Backend) tidyHistory(ctx context.Context, req *logical.Request) error {
+    cfg, err := b.config(ctx, req.Storage)
+    if err != nil {
+        return err
+    }
+    if cfg.HistoryRetention <= 0 {
+        return nil
+    }
+    cutoff := time.Now().Add(-cfg.HistoryRetention)
+
+    services, err := req.Storage.List(ctx, historyPrefix)
+    if err != nil {
+        return err
+    }
+    for _, svc := range services {
+        prefix := historyPrefix + svc
+        ids, err := req.Storage.List(ctx, prefix)
+        if err != nil {
+            return err
+        }
+        // the head record is kept so that the signed head always has a record
+        head, err := b.chainHead(ctx, req.Storage, strings.TrimSuffix(svc, "/"))
+        if err != nil {
+            return err
+        }
+        for _, id := range ids {
+            at, ok := historyTime(id)
+            if !ok || !at.Before(cutoff) || (head != nil && head.ID == id) {
+                continue
+            }
+            if err := req.Storage.Delete(ctx, prefix+id); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
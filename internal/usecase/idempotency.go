@@ -0,0 +1,130 @@
+// internal/usecase/idempotency.go
+package usecase
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+    idempotencyPrefix = "idempotency/"
+
+    defaultIdempotencyTTL = 24 * time.Hour
+)
+
+// idempotencyRecord is the outcome of a txn request stored under its idempotency key.
+type idempotencyRecord struct {
+    Fingerprint string                 `json:"fingerprint"`
+    Response    map[string]interface{} `json:"response"`
+    ExpiresAt   time.Time              `json:"expires_at"`
+}
+
+// requestFingerprint hashes the path and parameters of a request, without its idempotency key.
+func requestFingerprint(req *logical.Request) (string, error) {
+    params := make(map[string]interface{}, len(req.Data))
+    for k, v := range req.Data {
+        if k != "idempotency_key" {
+            params[k] = v
+        }
+    }
+    raw, err := json.Marshal(map[string]interface{}{"path": req.Path, "data": params})
+    if err != nil {
+        return "", err
+    }
+    sum := sha256.Sum256(raw)
+    return hex.EncodeToString(sum[:]), nil
+}
+
+func idempotencyStorageKey(svc, key string) string {
+    sum := sha256.Sum256([]byte(key))
+    return idempotencyPrefix + svc + "/" + hex.EncodeToString(sum[:])
+}
+
+// idempotentResponse returns the stored response of a retried request, an error
+// response when the key was used for other parameters, or nil for a new request.
+// A stored pending response only stands while its request is pending.
+func (b *Backend) idempotentResponse(ctx context.Context, req *logical.Request, svc, key, fingerprint string) (*logical.Response, error) {
+    entry, err := req.Storage.Get(ctx, idempotencyStorageKey(svc, key))
+    if err != nil || entry == nil {
+        return nil, err
+    }
+    rec := &idempotencyRecord{}
+    if err := entry.DecodeJSON(rec); err != nil {
+        return nil, err
+    }
+    if !time.Now().Before(rec.ExpiresAt) {
+        return nil, nil
+    }
+    if rec.Fingerprint != fingerprint {
+        return logical.ErrorResponse("idempotency_key %q was already used with different parameters", key), nil
+    }
+    // a request no longer pending was cancelled or expired unsigned: it may be submitted again
+    if rec.Response["status"] == "pending_approval" {
+        id, _ := rec.Response["id"].(string)
+        p, err := b.pendingRequest(ctx, req.Storage, svc, id)
+        if err != nil || p == nil || !time.Now().Before(p.ExpiresAt) {
+            return nil, err
+        }
+    }
+    rec.Response["idempotent_replay"] = true
+    return &logical.Response{Data: rec.Response}, nil
+}
+
+// storeIdempotentResponse keeps a successful or pending response for retries with the same key.
+func (b *Backend) storeIdempotentResponse(ctx context.Context, req *logical.Request, svc, key, fingerprint string, resp *logical.Response) error {
+    cfg, err := b.config(ctx, req.Storage)
+    if err != nil {
+        return err
+    }
+    entry, err := logical.StorageEntryJSON(idempotencyStorageKey(svc, key), &idempotencyRecord{
+        Fingerprint: fingerprint,
+        Response:    resp.Data,
+        ExpiresAt:   time.Now().Add(cfg.IdempotencyTTL).UTC(),
+    })
+    if err != nil {
+        return err
+    }
+    if err := req.Storage.Put(ctx, entry); err != nil {
+        b.Logger().Error("Failed to store idempotency record", "name", svc, "error", err)
+        return err
+    }
+    return nil
+}
+
+// tidyIdempotency deletes expired idempotency records.
+func (b *Backend) tidyIdempotency(ctx context.Context, req *logical.Request) error {
+    services, err := req.Storage.List(ctx, idempotencyPrefix)
+    if err != nil {
+        return err
+    }
+    now := time.Now()
+    for _, svc := range services {
+        prefix := idempotencyPrefix + strings.TrimSuffix(svc, "/") + "/"
+        keys, err := req.Storage.List(ctx, prefix)
+        if err != nil {
+            return err
+        }
+        for _, k := range keys {
+            entry, err := req.Storage.Get(ctx, prefix+k)
+            if err != nil {
+                return err
+            }
+            if entry == nil {
+                continue
+            }
+            rec := &idempotencyRecord{}
+            if err := entry.DecodeJSON(rec); err != nil || !now.Before(rec.ExpiresAt) {
+                if err := req.Storage.Delete(ctx, prefix+k); err != nil {
+                    return err
+                }
+            }
+        }
+    }
+    return nil
+}
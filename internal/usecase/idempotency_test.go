@@ -0,0 +1,104 @@
+// internal/usecase/idempotency_test.go
+
+package usecase
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "svc"}
+    _, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+
+    transfer := func(key, amount string) *logical.Response {
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/ton/transfer", "", map[string]interface{}{
+            "destination":     testDestination,
+            "amount":          amount,
+            "seqno":           4,
+            "idempotency_key": key,
+        })
+    }
+
+    first := transfer("payout-1", "1000")
+    require.False(t, first.IsError(), "%v", first.Data)
+    // the default valid_until moves on; a retry must not produce another message
+    time.Sleep(1100 * time.Millisecond)
+    retry := transfer("payout-1", "1000")
+    require.False(t, retry.IsError(), "%v", retry.Data)
+    assert.Equal(t, first.Data["signed_boc"], retry.Data["signed_boc"])
+    assert.Equal(t, true, retry.Data["idempotent_replay"])
+
+    resp := transfer("payout-1", "2000")
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "different parameters")
+
+    other := transfer("payout-2", "1000")
+    require.False(t, other.IsError(), "%v", other.Data)
+    assert.NotEqual(t, first.Data["signed_boc"], other.Data["signed_boc"])
+
+    // expired records are dropped by the periodic tidy and the key can sign again
+    key := idempotencyStorageKey("svc", "payout-1")
+    entry, err := storage.Get(context.Background(), key)
+    require.NoError(t, err)
+    rec := &idempotencyRecord{}
+    require.NoError(t, entry.DecodeJSON(rec))
+    rec.ExpiresAt = time.Now().Add(-time.Second)
+    entry, err = logical.StorageEntryJSON(key, rec)
+    require.NoError(t, err)
+    require.NoError(t, storage.Put(context.Background(), entry))
+    require.NoError(t, b.periodic(context.Background(), &logical.Request{Storage: storage}))
+    entry, err = storage.Get(context.Background(), key)
+    require.NoError(t, err)
+    assert.Nil(t, entry)
+    assert.False(t, transfer("payout-1", "2000").IsError())
+}
+
+func TestIdempotencyKeyPendingApproval(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers", "", map[string]interface{}{"serviceName": "svc"}))
+    requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers/svc/approvals", "", map[string]interface{}{
+        "required_approvals": 1,
+        "thresholds":         map[string]interface{}{"ton": "1000"},
+        "ttl":                "1h",
+    }))
+    transfer := func(key string) *logical.Response {
+        return requireOK(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/ton/transfer", "alice", map[string]interface{}{
+            "destination": testDestination, "amount": "5000", "seqno": 2, "idempotency_key": key,
+        }))
+    }
+
+    // a retry while pending returns the pending request instead of submitting another
+    pending := transfer("payout-1")
+    require.Equal(t, "pending_approval", pending.Data["status"])
+    id := pending.Data["id"].(string)
+    retry := transfer("payout-1")
+    assert.Equal(t, id, retry.Data["id"])
+    assert.Equal(t, true, retry.Data["idempotent_replay"])
+
+    // once signed, a retry returns the signed message
+    signed := requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers/svc/pending/"+id+"/approve", "bob", nil))
+    require.NotEmpty(t, signed.Data["signed_boc"])
+    retry = transfer("payout-1")
+    assert.Equal(t, signed.Data["signed_boc"], retry.Data["signed_boc"])
+    assert.Equal(t, true, retry.Data["idempotent_replay"])
+
+    // a cancelled request can be submitted again
+    id = transfer("payout-2").Data["id"].(string)
+    requireOK(t, doRequest(t, b, storage, logical.DeleteOperation, "key-managers/svc/pending/"+id, "bob", nil))
+    retry = transfer("payout-2")
+    assert.Equal(t, "pending_approval", retry.Data["status"])
+    assert.NotEqual(t, id, retry.Data["id"])
+    assert.Nil(t, retry.Data["idempotent_replay"])
+}
@@ -0,0 +1,178 @@
+// internal/usecase/index.go
+package usecase
+
+import (
+    "context"
+    "fmt"
+    "strings"
+
+    "github.com/hashicorp/vault/sdk/helper/locksutil"
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/tonkeeper/tongo/ton"
+)
+
+const (
+    addressIndexPrefix   = "index/addresses/"
+    publicKeyIndexPrefix = "index/public-keys/"
+    indexVersionPath     = "index/version"
+    indexVersion         = 1
+)
+
+// keyRef points from an address or public key to a key that owns it.
+type keyRef struct {
+    ServiceName string `json:"service_name"`
+    KeyID       int    `json:"key_id"`
+}
+
+// indexEntry lists the keys owning an address or public key, oldest first:
+// the same seed can be imported into several key‑managers.
+type indexEntry struct {
+    Refs []keyRef `json:"refs"`
+}
+
+// normalizeAddress parses any friendly or raw address form and returns the raw "wc:hex" form.
+func normalizeAddress(addr string) (string, error) {
+    id, err := ton.ParseAccountID(strings.TrimSpace(addr))
+    if err != nil {
+        return "", fmt.Errorf("invalid TON address %q: %w", addr, err)
+    }
+    return id.ToRaw(), nil
+}
+
+// indexPaths returns the reverse index entries of a key pair.
+func indexPaths(kp *KeyPair) ([]string, error) {
+    raw, err := normalizeAddress(kp.Address)
+    if err != nil {
+        return nil, err
+    }
+    return []string{addressIndexPrefix + raw, publicKeyIndexPrefix + strings.ToLower(kp.PublicKey)}, nil
+}
+
+// indexKeyPair adds a key pair to its reverse index entries.
+func (b *Backend) indexKeyPair(ctx context.Context, s logical.Storage, svc string, kp *KeyPair) error {
+    paths, err := indexPaths(kp)
+    if err != nil {
+        return err
+    }
+    ref := keyRef{ServiceName: svc, KeyID: kp.ID}
+    for _, p := range paths {
+        err := b.updateIndexEntry(ctx, s, p, func(refs []keyRef) []keyRef {
+            for _, r := range refs {
+                if r == ref {
+                    return refs
+                }
+            }
+            return append(refs, ref)
+        })
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// unindexKeyPair removes a key pair from its reverse index entries, keeping the
+// other keys owning the same address or public key.
+func (b *Backend) unindexKeyPair(ctx context.Context, s logical.Storage, svc string, kp *KeyPair) error {
+    paths, err := indexPaths(kp)
+    if err != nil {
+        return err
+    }
+    ref := keyRef{ServiceName: svc, KeyID: kp.ID}
+    for _, p := range paths {
+        err := b.updateIndexEntry(ctx, s, p, func(refs []keyRef) []keyRef {
+            kept := refs[:0]
+            for _, r := range refs {
+                if r != ref {
+                    kept = append(kept, r)
+                }
+            }
+            return kept
+        })
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// updateIndexEntry rewrites the refs of the index entry at p, deleting it once no key owns it.
+func (b *Backend) updateIndexEntry(ctx context.Context, s logical.Storage, p string, update func([]keyRef) []keyRef) error {
+    lock := locksutil.LockForKey(b.indexLocks, p)
+    lock.Lock()
+    defer lock.Unlock()
+
+    refs, err := lookupKeyRefs(ctx, s, p)
+    if err != nil {
+        return err
+    }
+    if refs = update(refs); len(refs) == 0 {
+        if err := s.Delete(ctx, p); err != nil {
+            b.Logger().Error("Failed to delete index entry", "path", p, "error", err)
+            return err
+        }
+        return nil
+    }
+    entry, err := logical.StorageEntryJSON(p, indexEntry{Refs: refs})
+    if err != nil {
+        return err
+    }
+    if err := s.Put(ctx, entry); err != nil {
+        b.Logger().Error("Failed to store index entry", "path", p, "error", err)
+        return err
+    }
+    return nil
+}
+
+func lookupKeyRefs(ctx context.Context, s logical.Storage, p string) ([]keyRef, error) {
+    entry, err := s.Get(ctx, p)
+    if err != nil {
+        return nil, err
+    }
+    if entry == nil {
+        return nil, nil
+    }
+    var e indexEntry
+    if err := entry.DecodeJSON(&e); err != nil {
+        return nil, err
+    }
+    return e.Refs, nil
+}
+
+// initialize backfills the reverse index for key‑managers written before it existed.
+func (b *Backend) initialize(ctx context.Context, req *logical.InitializationRequest) error {
+    s := req.Storage
+    entry, err := s.Get(ctx, indexVersionPath)
+    if err != nil {
+        return err
+    }
+    if entry != nil {
+        return nil
+    }
+
+    services, err := s.List(ctx, "key-managers/")
+    if err != nil {
+        return err
+    }
+    for _, svc := range services {
+        km, err := b.retrieveKeyManager(ctx, &logical.Request{Storage: s}, svc)
+        if err != nil {
+            return err
+        }
+        if km == nil {
+            continue
+        }
+        for _, kp := range km.KeyPairs {
+            if err := b.indexKeyPair(ctx, s, km.ServiceName, kp); err != nil {
+                return err
+            }
+        }
+    }
+
+    entry, err = logical.StorageEntryJSON(indexVersionPath, indexVersion)
+    if err != nil {
+        return err
+    }
+    b.Logger().Info("Reverse index built", "key_managers", len(services))
+    return s.Put(ctx, entry)
+}
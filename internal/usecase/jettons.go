@@ -0,0 +1,139 @@
+// internal/usecase/jettons.go
+package usecase
+
+import (
+    "encoding/base64"
+    "fmt"
+    "math/big"
+    "strings"
+
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+)
+
+const (
+    // jettonLayoutStandard is the TEP-74 reference wallet data:
+    // balance:Coins owner:MsgAddress jetton_master:MsgAddress jetton_wallet_code:^Cell
+    jettonLayoutStandard = "standard"
+    // jettonLayoutStablecoin is the wallet data of governed jettons such as USDT:
+    // status:uint4 balance:Coins owner:MsgAddress jetton_master:MsgAddress
+    jettonLayoutStablecoin = "stablecoin"
+
+    tonDecimals = 9
+)
+
+// jettonMaster is an allowlisted jetton master of a key‑manager.
+type jettonMaster struct {
+    Symbol       string `json:"symbol,omitempty"`
+    Decimals     int    `json:"decimals"`
+    WalletCode   string `json:"wallet_code"` // base64 BOC
+    WalletLayout string `json:"wallet_layout"`
+}
+
+// walletAddress computes the jetton wallet of owner from the master and the wallet code.
+func (j *jettonMaster) walletAddress(master, owner ton.AccountID) (ton.AccountID, error) {
+    raw, err := base64.StdEncoding.DecodeString(j.WalletCode)
+    if err != nil {
+        return ton.AccountID{}, fmt.Errorf("invalid wallet_code: %w", err)
+    }
+    cells, err := boc.DeserializeBoc(raw)
+    if err != nil || len(cells) != 1 {
+        return ton.AccountID{}, fmt.Errorf("wallet_code must be a BOC with one root cell")
+    }
+    code := cells[0]
+
+    data := boc.NewCell()
+    if j.WalletLayout == jettonLayoutStablecoin {
+        if err := data.WriteUint(0, 4); err != nil {
+            return ton.AccountID{}, err
+        }
+    }
+    if err := tlb.Marshal(data, tlb.Grams(0)); err != nil {
+        return ton.AccountID{}, err
+    }
+    if err := tlb.Marshal(data, owner.ToMsgAddress()); err != nil {
+        return ton.AccountID{}, err
+    }
+    if err := tlb.Marshal(data, master.ToMsgAddress()); err != nil {
+        return ton.AccountID{}, err
+    }
+    if j.WalletLayout != jettonLayoutStablecoin {
+        if err := data.AddRef(code); err != nil {
+            return ton.AccountID{}, err
+        }
+    }
+
+    state := tlb.StateInit{
+        Code: tlb.Maybe[tlb.Ref[boc.Cell]]{Exists: true, Value: tlb.Ref[boc.Cell]{Value: *code}},
+        Data: tlb.Maybe[tlb.Ref[boc.Cell]]{Exists: true, Value: tlb.Ref[boc.Cell]{Value: *data}},
+    }
+    cell := boc.NewCell()
+    if err := tlb.Marshal(cell, state); err != nil {
+        return ton.AccountID{}, err
+    }
+    hash, err := cell.Hash256()
+    if err != nil {
+        return ton.AccountID{}, err
+    }
+    return ton.AccountID{Workchain: master.Workchain, Address: hash}, nil
+}
+
+// parseUnits parses a human amount such as "12.5" into base units.
+func parseUnits(s string, decimals int, field string) (*big.Int, error) {
+    s = strings.TrimSpace(s)
+    whole, frac, _ := strings.Cut(s, ".")
+    if len(frac) > decimals {
+        return nil, fmt.Errorf("%s has more than %d decimals: %q", field, decimals, s)
+    }
+    v, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", decimals-len(frac)), 10)
+    if !ok || v.Sign() < 0 {
+        return nil, fmt.Errorf("%s must be a non-negative decimal amount, got %q", field, s)
+    }
+    return v, nil
+}
+
+// formatUnits formats base units as a human amount.
+func formatUnits(v *big.Int, decimals int) string {
+    s := v.String()
+    if decimals == 0 {
+        return s
+    }
+    if len(s) <= decimals {
+        s = strings.Repeat("0", decimals-len(s)+1) + s
+    }
+    whole, frac := s[:len(s)-decimals], strings.TrimRight(s[len(s)-decimals:], "0")
+    if frac == "" {
+        return whole
+    }
+    return whole + "." + frac
+}
+
+// jettonUsage names what relies on an allowlisted jetton master, or returns "".
+func (km *KeyManager) jettonUsage(master string) string {
+    if km.Limits[master] != nil {
+        return "a spending limit"
+    }
+    for _, kp := range km.KeyPairs {
+        if kp.Limits[master] != nil {
+            return fmt.Sprintf("a spending limit on key %d", kp.ID)
+        }
+    }
+    if km.Approvals != nil {
+        if _, ok := km.Approvals.Thresholds[master]; ok {
+            return "an approval threshold"
+        }
+    }
+    return ""
+}
+
+// assetDecimals returns the decimals of an asset, if known to the key‑manager.
+func (km *KeyManager) assetDecimals(asset string) (int, bool) {
+    if asset == assetTON {
+        return tonDecimals, true
+    }
+    if j := km.Jettons[asset]; j != nil {
+        return j.Decimals, true
+    }
+    return 0, false
+}
@@ -3,30 +3,245 @@ package usecase
 
 import (
     "context"
+    "crypto/ed25519"
+    "encoding/hex"
     "fmt"
+    "time"
+
+    "github.com/tonkeeper/tongo/wallet"
 
     "github.com/hashicorp/vault/sdk/framework"
     "github.com/hashicorp/vault/sdk/logical"
 )
 
+// defaultWalletVersion is the wallet contract used to derive addresses for new keys.
+var defaultWalletVersion = wallet.V4R2
+
 type KeyPair struct {
-    PrivateKey string `json:"private_key"`
-    PublicKey  string `json:"public_key"`
-    Address    string `json:"address"`
+    ID            int               `json:"id"`
+    PrivateKey    string            `json:"private_key"`
+    PublicKey     string            `json:"public_key"`
+    Address       string            `json:"address"`
+    WalletVersion string            `json:"wallet_version"`
+    Workchain     int               `json:"workchain"`
+    Network       string            `json:"network"`
+    Disabled      bool              `json:"disabled"`
+    Exportable    bool              `json:"exportable"`
+    RotatedFrom   int               `json:"rotated_from,omitempty"` // version this key replaced
+    Labels        []string          `json:"labels,omitempty"`
+    Metadata      map[string]string `json:"metadata,omitempty"`
+    Limits        spendingLimits    `json:"limits,omitempty"`
+    CreatedAt     time.Time         `json:"created_at"`
 }
 
+// KeyManager holds the keys of a service. Key ids double as key versions:
+// CurrentVersion is the key used for signing by default and keys below
+// MinSigningVersion may no longer sign, only verify. Versions only make sense
+// for a single address, so rotation and MinSigningVersion are refused for
+// key-managers holding several independent keys.
 type KeyManager struct {
-    ServiceName string     `json:"service_name"`
-    KeyPairs    []*KeyPair `json:"key_pairs"`
+    ServiceName       string            `json:"service_name"`
+    KeyPairs          []*KeyPair        `json:"key_pairs"`
+    LastKeyID         int               `json:"last_key_id"`
+    CurrentVersion    int               `json:"current_version"`
+    MinSigningVersion int               `json:"min_signing_version"`
+    Labels            []string          `json:"labels,omitempty"`
+    Metadata          map[string]string `json:"metadata,omitempty"`
+    Limits            spendingLimits    `json:"limits,omitempty"`
+
+    AllowedDestinations []string `json:"allowed_destinations,omitempty"` // raw addresses
+    DeniedDestinations  []string `json:"denied_destinations,omitempty"`  // raw addresses
+
+    // Jettons are the allowlisted jetton masters by raw address. When set, jetton
+    // transfers must name one of them.
+    Jettons map[string]*jettonMaster `json:"jettons,omitempty"`
+
+    // Approvals holds transfers above a threshold until distinct entities approve them.
+    Approvals *approvalPolicy `json:"approvals,omitempty"`
+
+    // Policy is evaluated against every transaction before it is signed.
+    Policy *signingPolicy `json:"policy,omitempty"`
+
+    // RateLimit throttles the sign and txn paths of the key‑manager.
+    RateLimit *rateLimit `json:"rate_limit,omitempty"`
+
+    // MessageLifetime overrides the mount message lifetime bounds.
+    MessageLifetime *messageLifetime `json:"message_lifetime,omitempty"`
+
+    // AllowSignatureDisable lets W5 extended actions turn signature authentication off.
+    AllowSignatureDisable bool `json:"allow_signature_disable,omitempty"`
+
+    // AllowAddExtension lets W5 extended actions install extensions, which may move funds without a signature.
+    AllowAddExtension bool `json:"allow_add_extension,omitempty"`
+
+    // AllowPluginInstall lets V4 wallets deploy and install the AllowedPlugins, which may
+    // request funds from the wallet without a signature.
+    AllowPluginInstall bool     `json:"allow_plugin_install,omitempty"`
+    AllowedPlugins     []string `json:"allowed_plugins,omitempty"` // raw form
+}
+
+// newKeyPair derives the public key and wallet address of a seed.
+func newKeyPair(seed []byte, ws walletSettings) (*KeyPair, error) {
+    priv := ed25519.NewKeyFromSeed(seed)     // 64-byte private key
+    pub := priv.Public().(ed25519.PublicKey) // 32-byte public key
+    address, err := deriveTonAddress(pub, ws)
+    if err != nil {
+        return nil, err
+    }
+    return &KeyPair{
+        PrivateKey:    hex.EncodeToString(seed),
+        PublicKey:     hex.EncodeToString(pub),
+        Address:       address,
+        WalletVersion: ws.Version.ToString(),
+        Workchain:     ws.Workchain,
+        Network:       ws.Network,
+        CreatedAt:     time.Now().UTC(),
+    }, nil
+}
+
+// walletSettings returns the wallet contract the key pair signs for.
+func (kp *KeyPair) walletSettings() (walletSettings, error) {
+    ver, err := wallet.VersionFromString(kp.WalletVersion)
+    if err != nil {
+        return walletSettings{}, fmt.Errorf("key %d has unsupported wallet version %q", kp.ID, kp.WalletVersion)
+    }
+    return walletSettings{Version: ver, Workchain: kp.Workchain, Network: kp.Network}, nil
+}
+
+// privateKey decodes the stored seed. Callers should zeroSeed the result when done.
+func (kp *KeyPair) privateKey() (ed25519.PrivateKey, error) {
+    seed, err := hex.DecodeString(kp.PrivateKey)
+    if err != nil {
+        return nil, fmt.Errorf("invalid stored seed hex: %w", err)
+    }
+    defer zeroSeed(seed)
+    return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// keyStatus reports whether the key pair may currently be used for signing.
+func (km *KeyManager) keyStatus(kp *KeyPair) string {
+    if kp.Disabled || kp.ID < km.MinSigningVersion {
+        return keyStatusDisabled
+    }
+    return keyStatusEnabled
+}
+
+// signingKey returns the key pair of the requested version, or of the current
+// version if version is 0, refusing versions that are not allowed to sign.
+func (km *KeyManager) signingKey(version int) (*KeyPair, error) {
+    if version == 0 {
+        version = km.CurrentVersion
+    }
+    kp := km.keyPairByID(version)
+    if kp == nil {
+        return nil, fmt.Errorf("key version %d not found in key-manager %q", version, km.ServiceName)
+    }
+    if kp.Disabled {
+        return nil, fmt.Errorf("key version %d of key-manager %q is disabled", version, km.ServiceName)
+    }
+    if km.keyStatus(kp) != keyStatusEnabled {
+        return nil, fmt.Errorf("key version %d of key-manager %q is disabled for signing (min_signing_version %d)",
+            version, km.ServiceName, km.MinSigningVersion)
+    }
+    return kp, nil
+}
+
+// independentKeys counts the key pairs that were not created by rotating another.
+func (km *KeyManager) independentKeys() int {
+    n := 0
+    for _, kp := range km.KeyPairs {
+        if kp.RotatedFrom == 0 {
+            n++
+        }
+    }
+    return n
+}
+
+// versioned reports whether the key-manager has been rotated or had older versions retired.
+func (km *KeyManager) versioned() bool {
+    return km.independentKeys() < len(km.KeyPairs) || km.MinSigningVersion > 0
+}
+
+// checkSingleKey refuses versioning a key-manager holding several independent keys.
+func (km *KeyManager) checkSingleKey() error {
+    if n := km.independentKeys(); n > 1 {
+        return fmt.Errorf("key-manager %q holds %d independent keys; rotation and min_signing_version only apply to single-key key-managers", km.ServiceName, n)
+    }
+    return nil
+}
+
+// rotate adds kp as the new current version of a single-key key-manager.
+func (km *KeyManager) rotate(kp *KeyPair) error {
+    if err := km.checkSingleKey(); err != nil {
+        return err
+    }
+    kp.RotatedFrom = km.CurrentVersion
+    km.addKeyPair(kp)
+    km.CurrentVersion = kp.ID
+    return nil
+}
+
+// addKeyPair assigns the next key id to kp and appends it to the manager.
+func (km *KeyManager) addKeyPair(kp *KeyPair) {
+    km.LastKeyID++
+    kp.ID = km.LastKeyID
+    km.KeyPairs = append(km.KeyPairs, kp)
+}
+
+// keyPairByID returns the key pair with the given id or nil.
+func (km *KeyManager) keyPairByID(id int) *KeyPair {
+    for _, kp := range km.KeyPairs {
+        if kp.ID == id {
+            return kp
+        }
+    }
+    return nil
+}
+
+// normalize fills in fields missing from entries written by older plugin versions.
+func (km *KeyManager) normalize() {
+    for _, kp := range km.KeyPairs {
+        if kp.ID == 0 {
+            km.LastKeyID++
+            kp.ID = km.LastKeyID
+        }
+        if kp.WalletVersion == "" {
+            kp.WalletVersion = defaultWalletVersion.ToString()
+        }
+        if kp.Network == "" {
+            kp.Network = networkMainnet
+        }
+    }
+    if km.CurrentVersion == 0 && len(km.KeyPairs) > 0 {
+        km.CurrentVersion = km.KeyPairs[0].ID
+    }
 }
 
 func paths(b *Backend) []*framework.Path {
     return []*framework.Path{
         pathCreateAndList(b),
         pathReadAndDelete(b),
+        pathListKeys(b),
+        pathReadUpdateKey(b),
         pathSign(b),
+        pathVerify(b),
+        pathTonProof(b),
+        pathSignData(b),
+        pathRotate(b),
+        pathConfig(b),
+        pathLimits(b),
+        pathDestinations(b),
+        pathJettons(b),
+        pathPolicy(b),
+        pathPolicyDryRun(b),
+        pathRateLimit(b),
+        pathMessageLifetime(b),
+        pathExport(b),
         pathTransferTon(b),
         pathTransferJetton(b),
+        pathTransferNFT(b),
+        pathV4Plugins(b),
+        pathV4PluginPolicy(b),
     }
 }
 
@@ -43,5 +258,18 @@ func (b *Backend) retrieveKeyManager(ctx context.Context, req *logical.Request,
     if err := entry.DecodeJSON(&km); err != nil {
         return nil, err
     }
+    km.normalize()
     return &km, nil
 }
+
+func (b *Backend) storeKeyManager(ctx context.Context, req *logical.Request, km *KeyManager) error {
+    entry, err := logical.StorageEntryJSON(fmt.Sprintf("key-managers/%s", km.ServiceName), km)
+    if err != nil {
+        return err
+    }
+    if err := req.Storage.Put(ctx, entry); err != nil {
+        b.Logger().Error("Failed to store key-manager", "name", km.ServiceName, "error", err)
+        return err
+    }
+    return nil
+}
@@ -0,0 +1,81 @@
+// internal/usecase/labels.go
+package usecase
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/helper/custommetadata"
+)
+
+const (
+    maxLabels      = 64
+    maxLabelLength = 128
+)
+
+// labelsFields returns the schema of the labels/metadata fields under the given prefix.
+func labelsFields(prefix, subject string) map[string]*framework.FieldSchema {
+    return map[string]*framework.FieldSchema{
+        prefix + "labels": {
+            Type:        framework.TypeCommaStringSlice,
+            Description: fmt.Sprintf("(Optional) Free-form labels of the %s. Replaces the existing labels.", subject),
+        },
+        prefix + "metadata": {
+            Type:        framework.TypeKVPairs,
+            Description: fmt.Sprintf("(Optional) Custom key/value metadata of the %s. Replaces the existing metadata.", subject),
+        },
+    }
+}
+
+// parseLabels returns the normalized labels of the field, or ok=false if it was not sent.
+func parseLabels(data *framework.FieldData, field string) (labels []string, ok bool, err error) {
+    raw, ok := data.GetOk(field)
+    if !ok {
+        return nil, false, nil
+    }
+    for _, l := range raw.([]string) {
+        l = strings.TrimSpace(l)
+        if l == "" || containsString(labels, l) {
+            continue
+        }
+        if len(l) > maxLabelLength {
+            return nil, true, fmt.Errorf("label %q is longer than %d characters", l, maxLabelLength)
+        }
+        labels = append(labels, l)
+    }
+    if len(labels) > maxLabels {
+        return nil, true, fmt.Errorf("at most %d labels are allowed, got %d", maxLabels, len(labels))
+    }
+    return labels, true, nil
+}
+
+// parseMetadata returns the validated metadata of the field, or ok=false if it was not sent.
+func parseMetadata(data *framework.FieldData, field string) (metadata map[string]string, ok bool, err error) {
+    raw, ok := data.GetOk(field)
+    if !ok {
+        return nil, false, nil
+    }
+    metadata = raw.(map[string]string)
+    if err := custommetadata.Validate(metadata); err != nil {
+        return nil, true, err
+    }
+    if len(metadata) == 0 {
+        metadata = nil
+    }
+    return metadata, true, nil
+}
+
+// mergeFields returns a copy of base with the given schemas added.
+func mergeFields(base map[string]*framework.FieldSchema, extra ...map[string]*framework.FieldSchema) map[string]*framework.FieldSchema {
+    fields := make(map[string]*framework.FieldSchema, len(base))
+    for k, v := range base {
+        fields[k] = v
+    }
+    for _, m := range extra {
+        for k, v := range m {
+            fields[k] = v
+        }
+    }
+    return fields
+}
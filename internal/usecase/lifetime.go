@@ -0,0 +1,55 @@
+// internal/usecase/lifetime.go
+package usecase
+
+import (
+    "fmt"
+    "time"
+)
+
+// messageLifetime bounds how long a signed wallet message stays valid. A zero
+// Default falls back to the mount default and a zero Max is unlimited.
+type messageLifetime struct {
+    Default time.Duration `json:"default"`
+    Max     time.Duration `json:"max"`
+}
+
+// messageLifetime merges the mount bounds with the overrides of km; the tighter maximum wins.
+func (c *backendConfig) messageLifetime(km *KeyManager) messageLifetime {
+    l := messageLifetime{Default: c.DefaultMessageLifetime, Max: c.MaxMessageLifetime}
+    if o := km.MessageLifetime; o != nil {
+        if o.Default > 0 {
+            l.Default = o.Default
+        }
+        if o.Max > 0 && (l.Max == 0 || o.Max < l.Max) {
+            l.Max = o.Max
+        }
+    }
+    if l.Max > 0 && l.Default > l.Max {
+        l.Default = l.Max
+    }
+    return l
+}
+
+// pendingValidUntil returns the default expiry of a message that may wait up to
+// ttl for approvals before it is signed: the whole ttl, within the maximum lifetime.
+func (l messageLifetime) pendingValidUntil(ttl time.Duration, now time.Time) time.Time {
+    if l.Max > 0 && ttl > l.Max {
+        ttl = l.Max
+    }
+    return now.Add(ttl)
+}
+
+// validUntil returns the effective expiry of a message: requested when set and
+// within bounds, now plus the default lifetime otherwise.
+func (l messageLifetime) validUntil(requested, now time.Time) (time.Time, error) {
+    if requested.IsZero() {
+        return now.Add(l.Default), nil
+    }
+    if !requested.After(now) {
+        return time.Time{}, fmt.Errorf("valid_until %d is in the past", requested.Unix())
+    }
+    if l.Max > 0 && requested.Sub(now) > l.Max {
+        return time.Time{}, fmt.Errorf("valid_until %d is more than the maximum message lifetime of %s away", requested.Unix(), l.Max)
+    }
+    return requested, nil
+}
@@ -0,0 +1,217 @@
+// internal/usecase/limits.go
+package usecase
+
+import (
+    "context"
+    "fmt"
+    "math/big"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+    spendingPrefix = "spending/"
+
+    // assetTON names TON in limits; jettons are named by the raw address of their master.
+    assetTON = "ton"
+)
+
+// spendingLimit caps the amount of one asset signed away, in base units.
+// Empty amounts are not limited.
+type spendingLimit struct {
+    MaxPerTx     string        `json:"max_per_tx,omitempty"`
+    MaxPerWindow string        `json:"max_per_window,omitempty"`
+    Window       time.Duration `json:"window,omitempty"`
+}
+
+// spendingLimits maps an asset to its limit.
+type spendingLimits map[string]*spendingLimit
+
+// spendEntry records an amount signed away by a key.
+type spendEntry struct {
+    At     time.Time `json:"at"`
+    KeyID  int       `json:"key_id"`
+    Asset  string    `json:"asset"`
+    Amount string    `json:"amount"`
+}
+
+// spendLedger holds the spend entries of a key‑manager still inside a limit window.
+type spendLedger struct {
+    Entries []spendEntry `json:"entries"`
+}
+
+// assetAmounts sums the amounts a transaction sends away, per asset. Jettons are
+// only counted when withJettons is set, for spending limits and approval thresholds.
+func assetAmounts(msgs []*outMessage, withJettons bool) (map[string]*big.Int, error) {
+    out := map[string]*big.Int{assetTON: new(big.Int)}
+    for _, m := range msgs {
+        out[assetTON].Add(out[assetTON], new(big.Int).SetUint64(m.Amount))
+        if m.JettonAmount == nil || !withJettons {
+            continue
+        }
+        if m.JettonMaster == nil {
+            return nil, fmt.Errorf("jetton_master is required while jetton limits or approval thresholds are set")
+        }
+        asset := m.JettonMaster.ToRaw()
+        if out[asset] == nil {
+            out[asset] = new(big.Int)
+        }
+        out[asset].Add(out[asset], m.JettonAmount)
+    }
+    return out, nil
+}
+
+// hasJettonLimits reports whether limits cap any jetton.
+func (l spendingLimits) hasJettonLimits() bool {
+    for asset := range l {
+        if asset != assetTON {
+            return true
+        }
+    }
+    return false
+}
+
+// check verifies amounts against the limits given what was already spent. Entries
+// are filtered by keep before being summed.
+func (l spendingLimits) check(scope string, amounts map[string]*big.Int, ledger *spendLedger, now time.Time, keep func(spendEntry) bool) error {
+    for asset, amount := range amounts {
+        limit := l[asset]
+        if limit == nil || amount.Sign() == 0 {
+            continue
+        }
+        if limit.MaxPerTx != "" {
+            ceiling, _ := new(big.Int).SetString(limit.MaxPerTx, 10)
+            if amount.Cmp(ceiling) > 0 {
+                return fmt.Errorf("spending limit exceeded: %s %s in one transaction, %s allows at most %s",
+                    amount, asset, scope, limit.MaxPerTx)
+            }
+        }
+        if limit.MaxPerWindow != "" {
+            ceiling, _ := new(big.Int).SetString(limit.MaxPerWindow, 10)
+            spent := ledger.spent(asset, now.Add(-limit.Window), keep)
+            total := new(big.Int).Add(spent, amount)
+            if total.Cmp(ceiling) > 0 {
+                return fmt.Errorf("spending limit exceeded: %s allows %s %s per %s, %s already spent and %s requested",
+                    scope, limit.MaxPerWindow, asset, limit.Window, spent, amount)
+            }
+        }
+    }
+    return nil
+}
+
+// maxWindow returns the longest window of the limits.
+func (l spendingLimits) maxWindow() time.Duration {
+    var longest time.Duration
+    for _, limit := range l {
+        if limit.Window > longest {
+            longest = limit.Window
+        }
+    }
+    return longest
+}
+
+func (ledger *spendLedger) spent(asset string, since time.Time, keep func(spendEntry) bool) *big.Int {
+    sum := new(big.Int)
+    for _, e := range ledger.Entries {
+        if e.Asset != asset || e.At.Before(since) || !keep(e) {
+            continue
+        }
+        if v, ok := new(big.Int).SetString(e.Amount, 10); ok {
+            sum.Add(sum, v)
+        }
+    }
+    return sum
+}
+
+// enforceSpendingLimits checks a transaction of kp against the key‑manager and
+// key limits and returns a function recording it once it has been signed.
+// Callers must hold the key‑manager spend lock until record returns.
+func (b *Backend) enforceSpendingLimits(
+    ctx context.Context,
+    s logical.Storage,
+    km *KeyManager,
+    kp *KeyPair,
+    msgs []*outMessage,
+) (record func() error, err error) {
+    if len(km.Limits) == 0 && len(kp.Limits) == 0 {
+        return func() error { return nil }, nil
+    }
+    if km.Limits[assetTON] != nil || kp.Limits[assetTON] != nil {
+        // the amount sent with mode 128 is unknown until the wallet runs it
+        for i, m := range msgs {
+            if m.carriesBalance() {
+                return nil, limitError{fmt.Errorf("message %d sends the whole wallet balance (mode %d), which TON spending limits cannot bound", i+1, m.message.Mode)}
+            }
+        }
+    }
+    amounts, err := assetAmounts(msgs, km.Limits.hasJettonLimits() || kp.Limits.hasJettonLimits())
+    if err != nil {
+        return nil, limitError{err}
+    }
+
+    ledger, err := b.spendLedger(ctx, s, km.ServiceName)
+    if err != nil {
+        return nil, err
+    }
+    now := time.Now().UTC()
+    all := func(spendEntry) bool { return true }
+    ownKey := func(e spendEntry) bool { return e.KeyID == kp.ID }
+    if err := km.Limits.check(fmt.Sprintf("key-manager %q", km.ServiceName), amounts, ledger, now, all); err != nil {
+        return nil, limitError{err}
+    }
+    if err := kp.Limits.check(fmt.Sprintf("key version %d", kp.ID), amounts, ledger, now, ownKey); err != nil {
+        return nil, limitError{err}
+    }
+
+    return func() error {
+        // keep only what a window can still see
+        window := km.Limits.maxWindow()
+        for _, other := range km.KeyPairs {
+            if w := other.Limits.maxWindow(); w > window {
+                window = w
+            }
+        }
+        kept := ledger.Entries[:0]
+        for _, e := range ledger.Entries {
+            if !e.At.Before(now.Add(-window)) {
+                kept = append(kept, e)
+            }
+        }
+        ledger.Entries = kept
+        if window > 0 {
+            for asset, amount := range amounts {
+                if amount.Sign() > 0 {
+                    ledger.Entries = append(ledger.Entries, spendEntry{At: now, KeyID: kp.ID, Asset: asset, Amount: amount.String()})
+                }
+            }
+        }
+        entry, err := logical.StorageEntryJSON(spendingPrefix+km.ServiceName, ledger)
+        if err != nil {
+            return err
+        }
+        if err := s.Put(ctx, entry); err != nil {
+            b.Logger().Error("Failed to store spend ledger", "name", km.ServiceName, "error", err)
+            return err
+        }
+        return nil
+    }, nil
+}
+
+func (b *Backend) spendLedger(ctx context.Context, s logical.Storage, svc string) (*spendLedger, error) {
+    entry, err := s.Get(ctx, spendingPrefix+svc)
+    if err != nil {
+        return nil, err
+    }
+    ledger := &spendLedger{}
+    if entry == nil {
+        return ledger, nil
+    }
+    if err := entry.DecodeJSON(ledger); err != nil {
+        return nil, err
+    }
+    return ledger, nil
+}
+
+// limitError marks errors caused by a request breaking a limit, as opposed to internal failures.
+type limitError struct{ error }
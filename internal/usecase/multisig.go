@@ -0,0 +1,327 @@
+// internal/usecase/multisig.go
+package usecase
+
+import (
+    "encoding/hex"
+    "fmt"
+    "math/big"
+    "strings"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+// Multisig v2 (ton-blockchain/multisig-contract-v2) messages and order actions
+const (
+    opMultisigNewOrder = 0xf718510f
+    opMultisigApprove  = 0xa762230f
+
+    multisigActionTransfer     = "transfer"
+    multisigActionUpdateParams = "update_params"
+
+    defaultNewOrderAmount = "200000000" // order deployment and storage
+    defaultApproveAmount  = "100000000"
+)
+
+// maxOrderSeqno asks the multisig to use its next order seqno.
+var maxOrderSeqno = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// multisigOrderCodeHash is the hash of the multisig v2 order code, which multisigs
+// deploy as a library.
+var multisigOrderCodeHash = ton.MustParseHash("a01e057fbd4288402b9898d78d67bd4e90254c93c5866879bc2d1d12865436bc")
+
+// multisigOrderAddress returns the address of order seqno of a multisig, as its
+// get_order_address getter does: the hash of a basechain state init whose code is
+// the order code library and whose data is the multisig address and the seqno.
+func multisigOrderAddress(multisig ton.AccountID, seqno *big.Int) (ton.AccountID, error) {
+    code := boc.NewCellExotic(boc.LibraryCell)
+    if err := code.WriteUint(uint64(boc.LibraryCell), 8); err != nil {
+        return ton.AccountID{}, err
+    }
+    if err := code.WriteBytes(multisigOrderCodeHash[:]); err != nil {
+        return ton.AccountID{}, err
+    }
+    data := boc.NewCell()
+    if err := tlb.Marshal(data, struct {
+        Multisig tlb.MsgAddress
+        Seqno    tlb.Uint256
+    }{multisig.ToMsgAddress(), tlb.Uint256(*seqno)}); err != nil {
+        return ton.AccountID{}, err
+    }
+    init := boc.NewCell()
+    if err := tlb.Marshal(init, tlb.StateInit{
+        Code: tlb.Maybe[tlb.Ref[boc.Cell]]{Exists: true, Value: tlb.Ref[boc.Cell]{Value: *code}},
+        Data: tlb.Maybe[tlb.Ref[boc.Cell]]{Exists: true, Value: tlb.Ref[boc.Cell]{Value: *data}},
+    }); err != nil {
+        return ton.AccountID{}, err
+    }
+    hash, err := init.Hash256()
+    if err != nil {
+        return ton.AccountID{}, err
+    }
+    return ton.AccountID{Workchain: 0, Address: hash}, nil
+}
+
+// multisigAction is one action of an order:
+//   send_message#f1381e5b mode:uint8 message:^MessageRelaxed
+//   update_multisig_params#1d0cfbd3 threshold:uint8 signers:^(Hashmap 8 MsgAddressInt) proposers:(HashmapE 8 MsgAddressInt)
+type multisigAction struct {
+    tlb.SumType
+    SendMessage *struct {
+        Mode    uint8
+        Message tlb.Message `tlb:"^"`
+    } `tlbSumType:"#f1381e5b"`
+    UpdateParams *struct {
+        Threshold uint8
+        Signers   tlb.Hashmap[tlb.Uint8, tlb.MsgAddress] `tlb:"^"`
+        Proposers tlb.HashmapE[tlb.Uint8, tlb.MsgAddress]
+    } `tlbSumType:"#1d0cfbd3"`
+}
+
+// multisigOrder is the action dictionary of an order, keyed by action index.
+type multisigOrder = tlb.Hashmap[tlb.Uint8, tlb.Ref[multisigAction]]
+
+// multisigNewOrder is the body of a new_order message to the multisig.
+type multisigNewOrder struct {
+    Magic          tlb.Magic `tlb:"#f718510f"`
+    QueryID        uint64
+    OrderSeqno     tlb.Uint256
+    Signer         bool
+    Index          uint8
+    ExpirationDate tlb.Uint48
+    Order          multisigOrder `tlb:"^"`
+}
+
+// multisigApprove is the body of an approve message to an order.
+type multisigApprove struct {
+    Magic       tlb.Magic `tlb:"#a762230f"`
+    QueryID     uint64
+    SignerIndex uint8
+}
+
+// multisigActionFields returns the schema of one order action.
+func multisigActionFields() map[string]*framework.FieldSchema {
+    return mergeFields(tonMessageFields(), map[string]*framework.FieldSchema{
+        "type": {
+            Type:          framework.TypeString,
+            Description:   "transfer (default) or update_params.",
+            Default:       multisigActionTransfer,
+            AllowedValues: []interface{}{multisigActionTransfer, multisigActionUpdateParams},
+        },
+        "body": {
+            Type:        framework.TypeString,
+            Description: "(Optional) Base64 BOC of the message body of a transfer. Replaces comment.",
+        },
+        "threshold": {
+            Type:        framework.TypeInt,
+            Description: "Approvals an order needs, for update_params.",
+        },
+        "signers": {
+            Type:        framework.TypeCommaStringSlice,
+            Description: "Signer addresses in index order, for update_params.",
+        },
+        "proposers": {
+            Type:        framework.TypeCommaStringSlice,
+            Description: "(Optional) Proposer addresses in index order, for update_params.",
+        },
+    })
+}
+
+// parseMultisigOrder builds the action dictionary of a new order.
+func parseMultisigOrder(raw []interface{}) (multisigOrder, error) {
+    if len(raw) == 0 {
+        return multisigOrder{}, fmt.Errorf("actions is required")
+    }
+    if len(raw) > 255 {
+        return multisigOrder{}, fmt.Errorf("an order carries at most 255 actions, got %d", len(raw))
+    }
+    keys := make([]tlb.Uint8, len(raw))
+    values := make([]tlb.Ref[multisigAction], len(raw))
+    for i, item := range raw {
+        fields, ok := item.(map[string]interface{})
+        if !ok {
+            return multisigOrder{}, fmt.Errorf("actions[%d] must be an object", i)
+        }
+        data := &framework.FieldData{Raw: fields, Schema: multisigActionFields()}
+        if err := data.Validate(); err != nil {
+            return multisigOrder{}, fmt.Errorf("actions[%d]: %w", i, err)
+        }
+        a, err := multisigActionFromData(data)
+        if err != nil {
+            return multisigOrder{}, fmt.Errorf("actions[%d]: %w", i, err)
+        }
+        keys[i], values[i] = tlb.Uint8(i), tlb.Ref[multisigAction]{Value: a}
+    }
+    return tlb.NewHashmap(keys, values), nil
+}
+
+func multisigActionFromData(data *framework.FieldData) (multisigAction, error) {
+    var a multisigAction
+    if data.Get("type").(string) == multisigActionUpdateParams {
+        threshold := data.Get("threshold").(int)
+        signers, err := multisigAddresses(data.Get("signers").([]string), "signers")
+        if err != nil {
+            return a, err
+        }
+        proposers, err := multisigAddresses(data.Get("proposers").([]string), "proposers")
+        if err != nil {
+            return a, err
+        }
+        if len(signers.Keys()) == 0 {
+            return a, fmt.Errorf("signers is required for update_params")
+        }
+        if threshold <= 0 || threshold > len(signers.Keys()) {
+            return a, fmt.Errorf("threshold must be between 1 and the number of signers, got %d", threshold)
+        }
+        a.SumType = "UpdateParams"
+        a.UpdateParams = &struct {
+            Threshold uint8
+            Signers   tlb.Hashmap[tlb.Uint8, tlb.MsgAddress] `tlb:"^"`
+            Proposers tlb.HashmapE[tlb.Uint8, tlb.MsgAddress]
+        }{uint8(threshold), signers, tlb.NewHashmapE(proposers.Keys(), proposers.Values())}
+        return a, nil
+    }
+
+    dest, bounce, err := parseDestination(data, "destination", "bounce")
+    if err != nil {
+        return a, err
+    }
+    amount, err := parseNanotons(data.Get("amount").(string), "amount")
+    if err != nil {
+        return a, err
+    }
+    mode := data.Get("mode").(int)
+    if mode < 0 || mode > 255 {
+        return a, fmt.Errorf("mode must fit in 8 bits")
+    }
+    msg := wallet.Message{Amount: tlb.Grams(amount), Address: dest, Bounce: bounce, Mode: uint8(mode)}
+    if s := strings.TrimSpace(data.Get("body").(string)); s != "" {
+        if msg.Body, err = singleCell(s, "body"); err != nil {
+            return a, err
+        }
+    } else if comment := data.Get("comment").(string); comment != "" {
+        if msg.Body, err = commentCell(comment); err != nil {
+            return a, err
+        }
+    }
+    internal, _, err := msg.ToInternal()
+    if err != nil {
+        return a, err
+    }
+    a.SumType = "SendMessage"
+    a.SendMessage = &struct {
+        Mode    uint8
+        Message tlb.Message `tlb:"^"`
+    }{uint8(mode), internal}
+    return a, nil
+}
+
+// multisigAddresses builds an index-keyed address dictionary.
+func multisigAddresses(addrs []string, field string) (tlb.Hashmap[tlb.Uint8, tlb.MsgAddress], error) {
+    if len(addrs) > 255 {
+        return tlb.Hashmap[tlb.Uint8, tlb.MsgAddress]{}, fmt.Errorf("%s holds at most 255 addresses", field)
+    }
+    keys := make([]tlb.Uint8, len(addrs))
+    values := make([]tlb.MsgAddress, len(addrs))
+    for i, s := range addrs {
+        id, err := ton.ParseAccountID(strings.TrimSpace(s))
+        if err != nil {
+            return tlb.Hashmap[tlb.Uint8, tlb.MsgAddress]{}, fmt.Errorf("invalid %s[%d] %q: %w", field, i, s, err)
+        }
+        keys[i], values[i] = tlb.Uint8(i), id.ToMsgAddress()
+    }
+    return tlb.NewHashmap(keys, values), nil
+}
+
+// decodeMultisigOrder returns what each action of an encoded order does.
+func decodeMultisigOrder(c *boc.Cell) ([]map[string]interface{}, error) {
+    var order multisigOrder
+    if err := tlb.Unmarshal(c, &order); err != nil {
+        return nil, fmt.Errorf("failed to decode order: %w", err)
+    }
+    return orderPreview(order)
+}
+
+// orderPreview describes the actions of an order, in index order.
+func orderPreview(order multisigOrder) ([]map[string]interface{}, error) {
+    out := make([]map[string]interface{}, 0, len(order.Keys()))
+    for _, item := range order.Items() {
+        preview, err := item.Value.Value.preview()
+        if err != nil {
+            return nil, fmt.Errorf("action %d: %w", item.Key, err)
+        }
+        preview["index"] = int(item.Key)
+        out = append(out, preview)
+    }
+    return out, nil
+}
+
+func (a *multisigAction) preview() (map[string]interface{}, error) {
+    switch {
+    case a.SendMessage != nil:
+        info := a.SendMessage.Message.Info.IntMsgInfo
+        if info == nil {
+            return nil, fmt.Errorf("send_message carries no internal message")
+        }
+        dest, err := ton.AccountIDFromTlb(info.Dest)
+        if err != nil || dest == nil {
+            return nil, fmt.Errorf("send_message has no destination")
+        }
+        out := map[string]interface{}{
+            "type":        multisigActionTransfer,
+            "destination": dest.ToRaw(),
+            "amount":      fmt.Sprint(uint64(info.Value.Grams)),
+            "bounce":      info.Bounce,
+            "mode":        int(a.SendMessage.Mode),
+        }
+        body := boc.Cell(a.SendMessage.Message.Body.Value)
+        if body.BitSize() > 0 || body.RefsSize() > 0 {
+            hash, err := body.Hash256()
+            if err != nil {
+                return nil, err
+            }
+            out["body_hash"] = hex.EncodeToString(hash[:])
+            var comment wallet.TextComment
+            if err := tlb.Unmarshal(&body, &comment); err == nil {
+                out["comment"] = string(comment)
+            } else {
+                body.ResetCounters()
+                if op, err := body.ReadUint(32); err == nil {
+                    out["op"] = fmt.Sprintf("0x%08x", op)
+                }
+            }
+        }
+        return out, nil
+    case a.UpdateParams != nil:
+        signers, err := addressList(a.UpdateParams.Signers.Items())
+        if err != nil {
+            return nil, err
+        }
+        proposers, err := addressList(a.UpdateParams.Proposers.Items())
+        if err != nil {
+            return nil, err
+        }
+        return map[string]interface{}{
+            "type":      multisigActionUpdateParams,
+            "threshold": int(a.UpdateParams.Threshold),
+            "signers":   signers,
+            "proposers": proposers,
+        }, nil
+    }
+    return nil, fmt.Errorf("unknown action")
+}
+
+func addressList(items []tlb.HashmapItem[tlb.Uint8, tlb.MsgAddress]) ([]string, error) {
+    out := make([]string, 0, len(items))
+    for _, item := range items {
+        id, err := ton.AccountIDFromTlb(item.Value)
+        if err != nil || id == nil {
+            return nil, fmt.Errorf("invalid address at index %d", item.Key)
+        }
+        out = append(out, id.ToRaw())
+    }
+    return out, nil
+}
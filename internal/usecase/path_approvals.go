@@ -0,0 +1,388 @@
+// internal/usecase/path_approvals.go
+
+package usecase
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "github.com/hashicorp/go-uuid"
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/helper/locksutil"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+func pathApprovals(b *Backend) []*framework.Path {
+    pendingID := "key-managers/" + framework.GenericNameRegex("name") + "/pending/" + framework.GenericNameRegex("id")
+    return []*framework.Path{
+        {
+            Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/approvals",
+            ExistenceCheck: b.pathExistenceCheck,
+            HelpSynopsis:   "Manage the M-of-N approval policy of a key‑manager",
+            HelpDescription: `
+GET     — return the approval policy
+POST    — require required_approvals distinct entities to approve transfers sending more than a
+          threshold of an asset (thresholds=ton=100000000000,<jetton master>=1000000, in base units)
+DELETE  — remove the policy
+A transfer above a threshold is not signed: it is stored as a pending request with its decoded
+preview and signed exactly as submitted once approved, unless it expires first.
+            `,
+            Fields: map[string]*framework.FieldSchema{
+                "name": {Type: framework.TypeString},
+                "required_approvals": {
+                    Type:        framework.TypeInt,
+                    Description: "Number of distinct entities that must approve a pending request.",
+                },
+                "thresholds": {
+                    Type:        framework.TypeKVPairs,
+                    Description: `Amount of an asset ("ton" or a jetton master) above which a transfer needs approvals, in base units.`,
+                },
+                "ttl": {
+                    Type:        framework.TypeDurationSecond,
+                    Description: "Time a pending request waits for approvals before it expires.",
+                    Default:     int(defaultApprovalTTL.Seconds()),
+                },
+            },
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.ReadOperation:   &framework.PathOperation{Callback: b.readApprovalPolicy},
+                logical.CreateOperation: &framework.PathOperation{Callback: b.writeApprovalPolicy},
+                logical.UpdateOperation: &framework.PathOperation{Callback: b.writeApprovalPolicy},
+                logical.DeleteOperation: &framework.PathOperation{Callback: b.deleteApprovalPolicy},
+            },
+        },
+        {
+            Pattern: "key-managers/" + framework.GenericNameRegex("name") + "/pending/?",
+            Fields: map[string]*framework.FieldSchema{
+                "name": {Type: framework.TypeString},
+            },
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.ListOperation: &framework.PathOperation{Callback: b.listPendingRequests},
+            },
+            HelpSynopsis: "List the pending requests of a key‑manager",
+        },
+        {
+            Pattern: pendingID,
+            Fields: map[string]*framework.FieldSchema{
+                "name": {Type: framework.TypeString},
+                "id":   {Type: framework.TypeString},
+            },
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.ReadOperation:   &framework.PathOperation{Callback: b.readPendingRequest},
+                logical.DeleteOperation: &framework.PathOperation{Callback: b.cancelPendingRequest},
+            },
+            HelpSynopsis:    "Read or cancel a pending request",
+            HelpDescription: "GET returns the decoded preview and approvals; DELETE cancels the request.",
+        },
+        {
+            Pattern:        pendingID + "/approve",
+            ExistenceCheck: b.pathExistenceCheck,
+            Fields: map[string]*framework.FieldSchema{
+                "name": {Type: framework.TypeString},
+                "id":   {Type: framework.TypeString},
+            },
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.CreateOperation: &framework.PathOperation{Callback: b.approvePendingRequest},
+                logical.UpdateOperation: &framework.PathOperation{Callback: b.approvePendingRequest},
+            },
+            HelpSynopsis: "Approve a pending request",
+            HelpDescription: `
+Records the approval of the calling entity. The requester cannot approve its own request and each
+entity counts once. With the last approval the request is signed and its response returned.
+            `,
+        },
+    }
+}
+
+// readApprovalPolicy handles GET key-managers/{name}/approvals
+func (b *Backend) readApprovalPolicy(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    out := map[string]interface{}{"service_name": km.ServiceName}
+    if p := km.Approvals; p != nil {
+        out["required_approvals"] = p.Required
+        out["thresholds"] = p.Thresholds
+        out["ttl"] = int64(p.TTL.Seconds())
+    }
+    return &logical.Response{Data: out}, nil
+}
+
+// writeApprovalPolicy handles POST key-managers/{name}/approvals
+func (b *Backend) writeApprovalPolicy(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    p := &approvalPolicy{
+        Required:   data.Get("required_approvals").(int),
+        Thresholds: map[string]string{},
+        TTL:        time.Duration(data.Get("ttl").(int)) * time.Second,
+    }
+    if p.Required < 1 {
+        return logical.ErrorResponse("required_approvals must be at least 1"), nil
+    }
+    if p.TTL <= 0 {
+        return logical.ErrorResponse("ttl must be positive"), nil
+    }
+    for asset, amount := range data.Get("thresholds").(map[string]string) {
+        normalized, err := normalizeAsset(asset)
+        if err != nil {
+            return logical.ErrorResponse(err.Error()), nil
+        }
+        if normalized != assetTON && km.Jettons[normalized] == nil {
+            return logical.ErrorResponse("jetton master %s is not allowlisted, allowlist it before setting a threshold", normalized), nil
+        }
+        v, err := parseAmount(amount, "threshold of "+asset)
+        if err != nil {
+            return logical.ErrorResponse(err.Error()), nil
+        }
+        p.Thresholds[normalized] = v.String()
+    }
+    if len(p.Thresholds) == 0 {
+        return logical.ErrorResponse("thresholds is required"), nil
+    }
+
+    km.Approvals = p
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return b.readApprovalPolicy(ctx, req, data)
+}
+
+// deleteApprovalPolicy handles DELETE key-managers/{name}/approvals
+func (b *Backend) deleteApprovalPolicy(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    km.Approvals = nil
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return nil, nil
+}
+
+// submitForApproval stores a transfer as a pending request instead of signing it.
+func (b *Backend) submitForApproval(
+    ctx context.Context,
+    req *logical.Request,
+    km *KeyManager,
+    kp *KeyPair,
+    params *txnParams,
+    validUntil time.Time,
+    msgs []*outMessage,
+) (*logical.Response, error) {
+    id, err := uuid.GenerateUUID()
+    if err != nil {
+        return nil, err
+    }
+    // the replay resolves the same key version and message expiry
+    raw := make(map[string]interface{}, len(req.Data)+2)
+    for k, v := range req.Data {
+        raw[k] = v
+    }
+    raw["version"] = kp.ID
+    raw["valid_until"] = int(validUntil.Unix())
+
+    now := time.Now().UTC()
+    p := &pendingRequest{
+        ID:          id,
+        ServiceName: km.ServiceName,
+        KeyID:       kp.ID,
+        Address:     kp.Address,
+        ValidUntil:  validUntil.UTC(),
+        Path:        req.Path,
+        Data:        raw,
+        Preview:     make([]map[string]interface{}, len(msgs)),
+        Details:     params.details,
+        Actions:     params.actions,
+        RequestedBy: req.EntityID,
+        Required:    km.Approvals.Required,
+        CreatedAt:   now,
+        ExpiresAt:   now.Add(km.Approvals.TTL),
+    }
+    // the message cannot be sent once it expires, so neither can it be approved
+    if validUntil.Before(p.ExpiresAt) {
+        p.ExpiresAt = validUntil.UTC()
+    }
+    for i, m := range msgs {
+        p.Preview[i] = m.preview()
+    }
+    if p.Fingerprint, err = approvalFingerprint(kp, validUntil, p.Preview, params); err != nil {
+        return nil, err
+    }
+    if params.idempotencyKey != "" {
+        if p.RequestFingerprint, err = requestFingerprint(req); err != nil {
+            return nil, err
+        }
+    }
+    if err := b.storePendingRequest(ctx, req.Storage, p); err != nil {
+        return nil, err
+    }
+
+    info := p.info(now)
+    info["status"] = "pending_approval"
+    return &logical.Response{Data: info}, nil
+}
+
+// listPendingRequests handles LIST key-managers/{name}/pending
+func (b *Backend) listPendingRequests(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    ids, err := req.Storage.List(ctx, pendingKey(km.ServiceName, ""))
+    if err != nil {
+        return nil, err
+    }
+    now := time.Now().UTC()
+    keys := make([]string, 0, len(ids))
+    infos := make(map[string]interface{}, len(ids))
+    for _, id := range ids {
+        p, err := b.pendingRequest(ctx, req.Storage, km.ServiceName, id)
+        if err != nil {
+            return nil, err
+        }
+        if p == nil {
+            continue
+        }
+        keys = append(keys, id)
+        infos[id] = p.info(now)
+    }
+    return logical.ListResponseWithInfo(keys, infos), nil
+}
+
+// readPendingRequest handles GET key-managers/{name}/pending/{id}
+func (b *Backend) readPendingRequest(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name, id := data.Get("name").(string), data.Get("id").(string)
+    p, err := b.pendingRequest(ctx, req.Storage, name, id)
+    if err != nil {
+        return nil, err
+    }
+    if p == nil {
+        return logical.ErrorResponse("pending request %q not found", id), nil
+    }
+    return &logical.Response{Data: p.info(time.Now().UTC())}, nil
+}
+
+// cancelPendingRequest handles DELETE key-managers/{name}/pending/{id}
+func (b *Backend) cancelPendingRequest(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name, id := data.Get("name").(string), data.Get("id").(string)
+    lock := locksutil.LockForKey(b.pendingLocks, id)
+    lock.Lock()
+    defer lock.Unlock()
+    p, err := b.pendingRequest(ctx, req.Storage, name, id)
+    if err != nil {
+        return nil, err
+    }
+    if p == nil {
+        return logical.ErrorResponse("pending request %q not found", id), nil
+    }
+    if err := req.Storage.Delete(ctx, pendingKey(name, id)); err != nil {
+        return nil, err
+    }
+    b.Logger().Info("Pending request cancelled", "name", name, "id", id, "requested_by", p.RequestedBy, "cancelled_by", req.EntityID)
+
+    info := p.info(time.Now().UTC())
+    info["status"] = "cancelled"
+    info["cancelled_by"] = req.EntityID
+    return &logical.Response{Data: info}, nil
+}
+
+// approvePendingRequest handles POST key-managers/{name}/pending/{id}/approve
+func (b *Backend) approvePendingRequest(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name, id := data.Get("name").(string), data.Get("id").(string)
+    if req.EntityID == "" {
+        return logical.ErrorResponse("approvals require a token bound to an identity entity"), nil
+    }
+
+    lock := locksutil.LockForKey(b.pendingLocks, id)
+    lock.Lock()
+    defer lock.Unlock()
+    p, err := b.pendingRequest(ctx, req.Storage, name, id)
+    if err != nil {
+        return nil, err
+    }
+    if p == nil {
+        return logical.ErrorResponse("pending request %q not found", id), nil
+    }
+    now := time.Now().UTC()
+    if !now.Before(p.ExpiresAt) {
+        if err := req.Storage.Delete(ctx, pendingKey(name, id)); err != nil {
+            return nil, err
+        }
+        return logical.ErrorResponse("pending request %q expired at %s", id, p.ExpiresAt.Format(time.RFC3339)), nil
+    }
+    if req.EntityID == p.RequestedBy {
+        return logical.ErrorResponse("the requester cannot approve its own request"), nil
+    }
+    // an approver may retry signing once enough approvals are in
+    if p.approvedBy(req.EntityID) && len(p.Approvals) < p.Required {
+        return logical.ErrorResponse("entity %q already approved request %q", req.EntityID, id), nil
+    }
+    if !p.approvedBy(req.EntityID) {
+        p.Approvals = append(p.Approvals, approval{EntityID: req.EntityID, At: now})
+        if err := b.storePendingRequest(ctx, req.Storage, p); err != nil {
+            return nil, err
+        }
+    }
+    if len(p.Approvals) < p.Required {
+        info := p.info(now)
+        info["status"] = "pending_approval"
+        return &logical.Response{Data: info}, nil
+    }
+
+    // replay the request as submitted, by its requester
+    replay := &logical.Request{
+        Operation:  logical.CreateOperation,
+        Path:       p.Path,
+        Data:       p.Data,
+        Storage:    req.Storage,
+        EntityID:   p.RequestedBy,
+        MountPoint: req.MountPoint,
+    }
+    resp, err := b.HandleRequest(context.WithValue(ctx, approvedRequestKey{}, p), replay)
+    if err != nil || resp == nil || resp.IsError() {
+        return resp, err
+    }
+    if err := req.Storage.Delete(ctx, pendingKey(name, id)); err != nil {
+        return nil, err
+    }
+    resp.Data["pending_id"] = p.ID
+    resp.Data["approvals"] = len(p.Approvals)
+    return resp, nil
+}
@@ -0,0 +1,108 @@
+// internal/usecase/path_approvals_test.go
+
+package usecase
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestApprovalWorkflow(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    doRequest(t, b, storage, logical.UpdateOperation, "key-managers", "", map[string]interface{}{"serviceName": "svc"})
+    resp := doRequest(t, b, storage, logical.UpdateOperation, "key-managers/svc/approvals", "", map[string]interface{}{
+        "required_approvals": 2,
+        "thresholds":         map[string]interface{}{"ton": "10000000000"},
+        "ttl":                "1h",
+    })
+    require.False(t, resp.IsError(), "%v", resp.Data)
+
+    transfer := func(amount string) *logical.Response {
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/ton/transfer", "alice", map[string]interface{}{
+            "destination": testDestination, "amount": amount, "seqno": 7,
+        })
+    }
+
+    // below the threshold is signed right away
+    resp = transfer("10000000000")
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.NotEmpty(t, resp.Data["signed_boc"])
+
+    // mode 128 carries the whole balance and always waits for approvals
+    resp = doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/ton/transfer", "alice", map[string]interface{}{
+        "destination": testDestination, "amount": "0", "mode": 128, "seqno": 7,
+    })
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, "pending_approval", resp.Data["status"])
+    assert.Nil(t, resp.Data["signed_boc"])
+    requireOK(t, doRequest(t, b, storage, logical.DeleteOperation, "key-managers/svc/pending/"+resp.Data["id"].(string), "alice", nil))
+
+    // above the threshold waits for approvals
+    resp = transfer("20000000000")
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, "pending_approval", resp.Data["status"])
+    assert.Nil(t, resp.Data["signed_boc"])
+    id := resp.Data["id"].(string)
+    preview := resp.Data["preview"].([]map[string]interface{})
+    assert.Equal(t, "20000000000", preview[0]["amount"])
+    // the message is pinned to stay valid while it waits for approvals
+    validUntil := resp.Data["valid_until"].(int64)
+    assert.InDelta(t, time.Now().Add(time.Hour).Unix(), validUntil, 5)
+
+    resp = doRequest(t, b, storage, logical.ListOperation, "key-managers/svc/pending/", "", nil)
+    assert.Equal(t, []string{id}, resp.Data["keys"])
+
+    approve := func(entity string) *logical.Response {
+        return doRequest(t, b, storage, logical.UpdateOperation, "key-managers/svc/pending/"+id+"/approve", entity, nil)
+    }
+    assert.True(t, approve("").IsError(), "approvals need an entity")
+    assert.True(t, approve("alice").IsError(), "requester cannot approve")
+    resp = approve("bob")
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, 1, resp.Data["approvals"])
+    assert.True(t, approve("bob").IsError(), "an entity counts once")
+
+    resp = approve("carol")
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.NotEmpty(t, resp.Data["signed_boc"])
+    assert.Equal(t, uint32(7), resp.Data["seqno"])
+    assert.Equal(t, id, resp.Data["pending_id"])
+    assert.Equal(t, validUntil, resp.Data["valid_until"])
+
+    resp = doRequest(t, b, storage, logical.ReadOperation, "key-managers/svc/pending/"+id, "", nil)
+    assert.True(t, resp.IsError(), "signed requests are removed")
+
+    // a replay signing anything else than what was approved is refused
+    id = transfer("20000000000").Data["id"].(string)
+    p, err := b.pendingRequest(context.Background(), storage, "svc", id)
+    require.NoError(t, err)
+    p.Data["amount"] = "30000000000"
+    require.NoError(t, b.storePendingRequest(context.Background(), storage, p))
+    approve("bob")
+    resp = approve("carol")
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "no longer signs what was approved")
+
+    // cancelled requests cannot be approved
+    resp = doRequest(t, b, storage, logical.DeleteOperation, "key-managers/svc/pending/"+id, "dave", nil)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, "dave", resp.Data["cancelled_by"])
+    assert.True(t, approve("bob").IsError())
+    assert.True(t, doRequest(t, b, storage, logical.DeleteOperation, "key-managers/svc/pending/"+id, "dave", nil).IsError(), "unknown ids are not found")
+
+    // expired requests cannot be approved
+    id = transfer("20000000000").Data["id"].(string)
+    p, err = b.pendingRequest(context.Background(), storage, "svc", id)
+    require.NoError(t, err)
+    p.ExpiresAt = p.CreatedAt.Add(-time.Second)
+    require.NoError(t, b.storePendingRequest(context.Background(), storage, p))
+    resp = approve("bob")
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "expired")
+}
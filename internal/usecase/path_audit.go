@@ -0,0 +1,147 @@
+// internal/usecase/path_audit.go
+
+package usecase
+
+import (
+    "context"
+    "crypto/ed25519"
+    "crypto/x509"
+    "encoding/hex"
+    "encoding/pem"
+    "sort"
+    "strings"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+func pathAudit(b *Backend) []*framework.Path {
+    return []*framework.Path{
+        {
+            Pattern: "audit/public-key",
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.ReadOperation: &framework.PathOperation{Callback: b.readAuditKey},
+            },
+            HelpSynopsis: "Return the Ed25519 public key signing the history chain heads.",
+            HelpDescription: `
+GET → public_key (hex) and public_key_pem. The key is generated on first use and shared by the
+whole mount; auditors use it to check exported chain heads offline.
+            `,
+        },
+        {
+            Pattern: "audit/verify",
+            Fields: map[string]*framework.FieldSchema{
+                "name": {
+                    Type:        framework.TypeString,
+                    Description: "(Optional) Key‑manager to verify. Defaults to every key‑manager with history.",
+                },
+            },
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.ReadOperation:   &framework.PathOperation{Callback: b.verifyAudit},
+                logical.UpdateOperation: &framework.PathOperation{Callback: b.verifyAudit},
+            },
+            HelpSynopsis: "Verify the hash chain of the signing history",
+            HelpDescription: `
+Walks the history of each key‑manager from its oldest kept record: every record must hash to its
+record_hash, follow the previous record by seq and prev_hash, and the last record must be the
+chain head signed by the audit key. Reports the first broken link of each chain.
+            `,
+        },
+    }
+}
+
+// readAuditKey handles GET audit/public-key
+func (b *Backend) readAuditKey(
+    ctx context.Context,
+    req *logical.Request,
+    _ *framework.FieldData,
+) (*logical.Response, error) {
+    key, err := b.auditKey(ctx, req.Storage)
+    if err != nil {
+        return nil, err
+    }
+    pub := key.Public().(ed25519.PublicKey)
+    der, err := x509.MarshalPKIXPublicKey(pub)
+    if err != nil {
+        return nil, err
+    }
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "algorithm":      "ed25519",
+            "public_key":     hex.EncodeToString(pub),
+            "public_key_pem": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+        },
+    }, nil
+}
+
+// verifyAudit handles GET/POST audit/verify
+func (b *Backend) verifyAudit(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    key, err := b.auditKey(ctx, req.Storage)
+    if err != nil {
+        return nil, err
+    }
+    pub := key.Public().(ed25519.PublicKey)
+
+    var services []string
+    if name := data.Get("name").(string); name != "" {
+        services = []string{name}
+    } else {
+        listed, err := req.Storage.List(ctx, historyPrefix)
+        if err != nil {
+            return nil, err
+        }
+        heads, err := req.Storage.List(ctx, auditHeadsPrefix)
+        if err != nil {
+            return nil, err
+        }
+        for _, svc := range append(listed, heads...) {
+            svc = strings.TrimSuffix(svc, "/")
+            if !containsString(services, svc) {
+                services = append(services, svc)
+            }
+        }
+        sort.Strings(services)
+    }
+
+    valid := true
+    var firstBroken map[string]interface{}
+    chains := make(map[string]interface{}, len(services))
+    for _, svc := range services {
+        n, broken, err := b.verifyChain(ctx, req.Storage, svc, pub)
+        if err != nil {
+            return nil, err
+        }
+        chain := map[string]interface{}{"valid": broken == nil, "entries": n}
+        head, err := b.chainHead(ctx, req.Storage, svc)
+        if err != nil {
+            return nil, err
+        }
+        if head != nil {
+            chain["head"] = map[string]interface{}{
+                "seq":         head.Seq,
+                "id":          head.ID,
+                "record_hash": head.RecordHash,
+                "signature":   head.Signature,
+            }
+        }
+        if broken != nil {
+            chain["broken_at"] = broken.ID
+            chain["reason"] = broken.Reason
+            if valid {
+                firstBroken = map[string]interface{}{"service_name": svc, "id": broken.ID, "reason": broken.Reason}
+            }
+            valid = false
+        }
+        chains[svc] = chain
+    }
+
+    out := map[string]interface{}{"valid": valid, "chains": chains}
+    if firstBroken != nil {
+        out["first_broken"] = firstBroken
+    }
+    return &logical.Response{Data: out}, nil
+}
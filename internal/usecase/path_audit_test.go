@@ -0,0 +1,108 @@
+// internal/usecase/path_audit_test.go
+
+package usecase
+
+import (
+    "context"
+    "crypto/ed25519"
+    "encoding/hex"
+    "encoding/json"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestAuditChain(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers", "", map[string]interface{}{"serviceName": "svc"}))
+    for i := 0; i < 3; i++ {
+        requireOK(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/ton/transfer", "", map[string]interface{}{
+            "destination": testDestination, "amount": "1000", "seqno": i + 1,
+        }))
+    }
+
+    resp := requireOK(t, doRequest(t, b, storage, logical.ReadOperation, "audit/verify", "", nil))
+    assert.Equal(t, true, resp.Data["valid"])
+    chain := resp.Data["chains"].(map[string]interface{})["svc"].(map[string]interface{})
+    assert.Equal(t, 3, chain["entries"])
+
+    // the head signature checks out offline with the exported key
+    pubHex := requireOK(t, doRequest(t, b, storage, logical.ReadOperation, "audit/public-key", "", nil)).Data["public_key"].(string)
+    pub, err := hex.DecodeString(pubHex)
+    require.NoError(t, err)
+    head := chain["head"].(map[string]interface{})
+    sig, err := hex.DecodeString(head["signature"].(string))
+    require.NoError(t, err)
+    h := &chainHead{ServiceName: "svc", Seq: head["seq"].(uint64), RecordHash: head["record_hash"].(string)}
+    assert.True(t, ed25519.Verify(pub, h.message(), sig))
+
+    ids, err := storage.List(context.Background(), historyPrefix+"svc/")
+    require.NoError(t, err)
+    require.Len(t, ids, 3)
+
+    // an edited record breaks the chain at that record
+    key := historyPrefix + "svc/" + ids[1]
+    entry, err := storage.Get(context.Background(), key)
+    require.NoError(t, err)
+    var record map[string]interface{}
+    require.NoError(t, json.Unmarshal(entry.Value, &record))
+    original := entry.Value
+    record["messages"].([]interface{})[0].(map[string]interface{})["amount"] = "999999"
+    entry.Value, err = json.Marshal(record)
+    require.NoError(t, err)
+    require.NoError(t, storage.Put(context.Background(), entry))
+
+    resp = requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "audit/verify", "", map[string]interface{}{"name": "svc"}))
+    assert.Equal(t, false, resp.Data["valid"])
+    broken := resp.Data["first_broken"].(map[string]interface{})
+    assert.Equal(t, ids[1], broken["id"])
+    assert.Contains(t, broken["reason"], "hash mismatch")
+
+    // so does a deleted one
+    require.NoError(t, storage.Put(context.Background(), &logical.StorageEntry{Key: key, Value: original}))
+    assert.Equal(t, true, requireOK(t, doRequest(t, b, storage, logical.ReadOperation, "audit/verify", "", nil)).Data["valid"])
+    require.NoError(t, storage.Delete(context.Background(), key))
+    resp = requireOK(t, doRequest(t, b, storage, logical.ReadOperation, "audit/verify", "", nil))
+    assert.Equal(t, false, resp.Data["valid"])
+    assert.Equal(t, ids[2], resp.Data["first_broken"].(map[string]interface{})["id"])
+
+    // and a dropped last record no longer matches the signed head
+    require.NoError(t, storage.Put(context.Background(), &logical.StorageEntry{Key: key, Value: original}))
+    require.NoError(t, storage.Delete(context.Background(), historyPrefix+"svc/"+ids[2]))
+    resp = requireOK(t, doRequest(t, b, storage, logical.ReadOperation, "audit/verify", "", nil))
+    assert.Equal(t, false, resp.Data["valid"])
+    assert.Contains(t, resp.Data["first_broken"].(map[string]interface{})["reason"], "signed head")
+}
+
+// TestAuditChainClockStepBack checks that the chain is walked by seq, not by id:
+// a wall clock stepping back between two records keeps it valid.
+func TestAuditChainClockStepBack(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers", "", map[string]interface{}{"serviceName": "svc"}))
+    for i := 0; i < 2; i++ {
+        requireOK(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/ton/transfer", "", map[string]interface{}{
+            "destination": testDestination, "amount": "1000", "seqno": i + 1,
+        }))
+    }
+    ids, err := storage.List(context.Background(), historyPrefix+"svc/")
+    require.NoError(t, err)
+    require.Len(t, ids, 2)
+    entry, err := storage.Get(context.Background(), historyPrefix+"svc/"+ids[1])
+    require.NoError(t, err)
+    last := &historyEntry{}
+    require.NoError(t, entry.DecodeJSON(last))
+    last.ID = "00000000000000000001-00000000"
+    last.RecordHash, err = last.recordHash()
+    require.NoError(t, err)
+    entry, err = logical.StorageEntryJSON(historyPrefix+"svc/"+last.ID, last)
+    require.NoError(t, err)
+    require.NoError(t, storage.Put(context.Background(), entry))
+    require.NoError(t, storage.Delete(context.Background(), historyPrefix+"svc/"+ids[1]))
+    require.NoError(t, b.storeChainHead(context.Background(), storage, last))
+    resp := requireOK(t, doRequest(t, b, storage, logical.ReadOperation, "audit/verify", "", nil))
+    assert.Equal(t, true, resp.Data["valid"], "%v", resp.Data)
+}
@@ -0,0 +1,274 @@
+// internal/usecase/path_backup.go
+
+package usecase
+
+import (
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+// backupFormatVersion is bumped whenever backupPayload changes incompatibly.
+const backupFormatVersion = 1
+
+// backupHeader describes a backup in the clear. It is repeated inside the
+// encrypted payload so that tampering with it is detected on restore.
+type backupHeader struct {
+    FormatVersion int       `json:"format_version"`
+    ServiceName   string    `json:"service_name"`
+    CreatedAt     time.Time `json:"created_at"`
+    Algorithm     string    `json:"algorithm"`
+}
+
+// backupEnvelope is the blob returned by the backup endpoint, JSON then base64 encoded.
+type backupEnvelope struct {
+    backupHeader
+    KDF        *argon2Params `json:"kdf,omitempty"`
+    Ciphertext []byte        `json:"ciphertext"`
+}
+
+type backupPayload struct {
+    Header     backupHeader `json:"header"`
+    KeyManager *KeyManager  `json:"key_manager"`
+}
+
+func pathBackup(b *Backend) []*framework.Path {
+    return []*framework.Path{
+        {
+            Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/backup",
+            ExistenceCheck: b.pathExistenceCheck,
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.CreateOperation: &framework.PathOperation{Callback: b.backupKeyManager},
+                logical.UpdateOperation: &framework.PathOperation{Callback: b.backupKeyManager},
+            },
+            HelpSynopsis: "Back up a key‑manager as an encrypted blob.",
+            HelpDescription: `
+POST name, passphrase | public_key → backup(base64).
+The whole key‑manager (keys, versions, labels, metadata and counters) is encrypted either
+with AES-256-GCM under an Argon2id key derived from passphrase, or to public_key as in the
+export endpoint. To restore into another mount, pass that mount's wrapping_key as public_key.
+Only key‑managers whose keys are all exportable can be backed up.
+            `,
+            Fields: map[string]*framework.FieldSchema{
+                "name": {Type: framework.TypeString},
+                "passphrase": {
+                    Type:        framework.TypeString,
+                    Description: "Passphrase protecting the backup. Mutually exclusive with public_key.",
+                },
+                "public_key": {
+                    Type:        framework.TypeString,
+                    Description: "PEM RSA or X25519 public key the backup is encrypted to. Mutually exclusive with passphrase.",
+                },
+            },
+        },
+        {
+            Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/restore",
+            ExistenceCheck: b.pathExistenceCheck,
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.CreateOperation: &framework.PathOperation{Callback: b.restoreKeyManager},
+                logical.UpdateOperation: &framework.PathOperation{Callback: b.restoreKeyManager},
+            },
+            HelpSynopsis: "Restore a key‑manager from a backup blob.",
+            HelpDescription: `
+POST name, backup, passphrase, force → restores the key‑manager under name.
+Passphrase backups need the passphrase; RSA backups must be encrypted to this mount's
+wrapping_key. An existing key‑manager is only overwritten with force=true.
+            `,
+            Fields: map[string]*framework.FieldSchema{
+                "name": {Type: framework.TypeString},
+                "backup": {
+                    Type:        framework.TypeString,
+                    Description: "Backup blob returned by key-managers/:name/backup.",
+                },
+                "passphrase": {
+                    Type:        framework.TypeString,
+                    Description: "Passphrase of a passphrase-protected backup.",
+                },
+                "force": {
+                    Type:        framework.TypeBool,
+                    Description: "Overwrite an existing key‑manager with the same name.",
+                },
+            },
+        },
+    }
+}
+
+func (b *Backend) backupKeyManager(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    passphrase := data.Get("passphrase").(string)
+    publicKey := data.Get("public_key").(string)
+    if (passphrase == "") == (publicKey == "") {
+        return logical.ErrorResponse("exactly one of passphrase or public_key is required"), nil
+    }
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    for _, kp := range km.KeyPairs {
+        if !kp.Exportable {
+            return logical.ErrorResponse("key version %d of key-manager %q is not exportable", kp.ID, name), nil
+        }
+    }
+
+    env := &backupEnvelope{backupHeader: backupHeader{
+        FormatVersion: backupFormatVersion,
+        ServiceName:   km.ServiceName,
+        CreatedAt:     time.Now().UTC(),
+    }}
+    var recipient interface{}
+    if passphrase != "" {
+        env.Algorithm = encAlgPassphrase
+        if env.KDF, err = newArgon2Params(); err != nil {
+            return nil, err
+        }
+    } else {
+        if recipient, err = parseRecipientKey(publicKey); err != nil {
+            return logical.ErrorResponse(err.Error()), nil
+        }
+        env.Algorithm = recipientAlgorithm(recipient)
+    }
+    plaintext, err := json.Marshal(backupPayload{Header: env.backupHeader, KeyManager: km})
+    if err != nil {
+        return nil, err
+    }
+    defer zeroSeed(plaintext)
+
+    if passphrase != "" {
+        key := env.KDF.key(passphrase)
+        defer zeroSeed(key)
+        env.Ciphertext, err = sealAESGCM(key, plaintext, nil)
+    } else {
+        env.Ciphertext, err = sealToKey(recipient, plaintext)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to encrypt backup: %w", err)
+    }
+
+    blob, err := json.Marshal(env)
+    if err != nil {
+        return nil, err
+    }
+    b.Logger().Info("Key-manager backed up", "name", name, "algorithm", env.Algorithm)
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "service_name":   km.ServiceName,
+            "format_version": env.FormatVersion,
+            "algorithm":      env.Algorithm,
+            "backup":         base64.StdEncoding.EncodeToString(blob),
+        },
+    }, nil
+}
+
+func (b *Backend) restoreKeyManager(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+
+    raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data.Get("backup").(string)))
+    if err != nil {
+        return logical.ErrorResponse("backup must be base64: %s", err), nil
+    }
+    var env backupEnvelope
+    if err := json.Unmarshal(raw, &env); err != nil {
+        return logical.ErrorResponse("invalid backup: %s", err), nil
+    }
+    if env.FormatVersion != backupFormatVersion {
+        return logical.ErrorResponse("unsupported backup format version %d", env.FormatVersion), nil
+    }
+
+    var plaintext []byte
+    switch env.Algorithm {
+    case encAlgPassphrase:
+        passphrase := data.Get("passphrase").(string)
+        if passphrase == "" || env.KDF == nil {
+            return logical.ErrorResponse("passphrase is required to restore this backup"), nil
+        }
+        if err := env.KDF.validate(); err != nil {
+            return logical.ErrorResponse("invalid backup: %s", err), nil
+        }
+        key := env.KDF.key(passphrase)
+        defer zeroSeed(key)
+        plaintext, err = openAESGCM(key, env.Ciphertext, nil)
+    case encAlgRSA:
+        wk, wkErr := b.wrappingKey(ctx, req.Storage)
+        if wkErr != nil {
+            return nil, wkErr
+        }
+        plaintext, err = decryptRSAHybrid(wk, env.Ciphertext)
+    default:
+        return logical.ErrorResponse("backups encrypted with %q cannot be restored by the plugin", env.Algorithm), nil
+    }
+    if err != nil {
+        return logical.ErrorResponse("failed to decrypt backup: %s", err), nil
+    }
+    defer zeroSeed(plaintext)
+
+    var payload backupPayload
+    if err := json.Unmarshal(plaintext, &payload); err != nil {
+        return logical.ErrorResponse("invalid backup payload: %s", err), nil
+    }
+    if !payload.Header.CreatedAt.Equal(env.CreatedAt) || payload.Header.ServiceName != env.ServiceName ||
+        payload.Header.FormatVersion != env.FormatVersion || payload.Header.Algorithm != env.Algorithm ||
+        payload.KeyManager == nil {
+        return logical.ErrorResponse("backup header does not match its encrypted payload"), nil
+    }
+
+    existing, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil {
+        return nil, err
+    }
+    if existing == nil {
+        cfg, err := b.config(ctx, req.Storage)
+        if err != nil {
+            return nil, err
+        }
+        if err := b.checkKeyLimits(ctx, req, cfg, &KeyManager{ServiceName: name}, true); err != nil {
+            return logical.ErrorResponse(err.Error()), nil
+        }
+    } else {
+        if !data.Get("force").(bool) {
+            return logical.ErrorResponse("key-manager %q already exists, use force=true to overwrite it", name), nil
+        }
+        for _, kp := range existing.KeyPairs {
+            if err := b.unindexKeyPair(ctx, req.Storage, existing.ServiceName, kp); err != nil {
+                return nil, err
+            }
+        }
+    }
+
+    km := payload.KeyManager
+    km.ServiceName = name
+    km.normalize()
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    for _, kp := range km.KeyPairs {
+        if err := b.indexKeyPair(ctx, req.Storage, km.ServiceName, kp); err != nil {
+            return nil, err
+        }
+    }
+
+    b.Logger().Info("Key-manager restored", "name", name, "from", env.ServiceName, "backup_created_at", env.CreatedAt)
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "service_name":      km.ServiceName,
+            "restored_from":     env.ServiceName,
+            "backup_created_at": env.CreatedAt,
+            "current_version":   km.CurrentVersion,
+            "keys":              len(km.KeyPairs),
+        },
+    }, nil
+}
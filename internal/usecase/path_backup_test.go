@@ -0,0 +1,144 @@
+// internal/usecase/path_backup_test.go
+
+package usecase
+
+import (
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{
+        "serviceName":      "svc",
+        "privateKey":       testSeed,
+        "exportable":       true,
+        "service_metadata": map[string]interface{}{"team": "payments"},
+    }
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    address := resp.Data["address"]
+
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/rotate")
+    req.Storage = storage
+    _, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+
+    // Passphrase backup
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/backup")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"passphrase": "correct horse battery staple"}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, encAlgPassphrase, resp.Data["algorithm"])
+    backup := resp.Data["backup"].(string)
+
+    // Restore into another mount
+    b2, storage2 := newTestBackend(t)
+    restore := func(data map[string]interface{}) *logical.Response {
+        return doRequest(t, b2, storage2, logical.CreateOperation, "key-managers/svc/restore", "", data)
+    }
+    resp = restore(map[string]interface{}{"backup": backup, "passphrase": "wrong"})
+    assert.True(t, resp.IsError())
+
+    resp = restore(map[string]interface{}{"backup": backup, "passphrase": "correct horse battery staple"})
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, 2, resp.Data["current_version"])
+    assert.Equal(t, 2, resp.Data["keys"])
+
+    req = logical.TestRequest(t, logical.ReadOperation, "key-managers/svc")
+    req.Storage = storage2
+    resp, err = b2.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, map[string]string{"team": "payments"}, resp.Data["metadata"])
+    assert.Contains(t, resp.Data["addresses"], address)
+
+    req = logical.TestRequest(t, logical.ReadOperation, "addresses/"+address.(string))
+    req.Storage = storage2
+    resp, err = b2.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, "svc", resp.Data["service_name"])
+
+    // Refuse to overwrite unless forced
+    resp = restore(map[string]interface{}{"backup": backup, "passphrase": "correct horse battery staple"})
+    assert.True(t, resp.IsError())
+    resp = restore(map[string]interface{}{"backup": backup, "passphrase": "correct horse battery staple", "force": true})
+    assert.False(t, resp.IsError())
+
+    // Tampered header is detected
+    raw, err := base64.StdEncoding.DecodeString(backup)
+    require.NoError(t, err)
+    var env backupEnvelope
+    require.NoError(t, json.Unmarshal(raw, &env))
+    env.ServiceName = "other"
+    raw, err = json.Marshal(env)
+    require.NoError(t, err)
+    resp = restore(map[string]interface{}{
+        "backup":     base64.StdEncoding.EncodeToString(raw),
+        "passphrase": "correct horse battery staple",
+        "force":      true,
+    })
+    assert.True(t, resp.IsError())
+
+    // KDF parameters outside the server-side bounds are refused before deriving the key
+    for _, kdf := range []argon2Params{
+        {Salt: env.KDF.Salt, Time: 0, Memory: 64 * 1024, Threads: 4},
+        {Salt: env.KDF.Salt, Time: 3, Memory: 64 * 1024, Threads: 0},
+        {Salt: env.KDF.Salt, Time: 3, Memory: 1 << 31, Threads: 4},
+        {Salt: env.KDF.Salt, Time: 1 << 30, Memory: 64 * 1024, Threads: 4},
+    } {
+        crafted := env
+        crafted.ServiceName = "svc"
+        crafted.KDF = &kdf
+        raw, err = json.Marshal(crafted)
+        require.NoError(t, err)
+        resp = restore(map[string]interface{}{
+            "backup":     base64.StdEncoding.EncodeToString(raw),
+            "passphrase": "correct horse battery staple",
+            "force":      true,
+        })
+        require.True(t, resp.IsError(), "%+v", kdf)
+        assert.Contains(t, resp.Error().Error(), "kdf")
+    }
+
+    // Backup encrypted to the target mount's wrapping key
+    req = logical.TestRequest(t, logical.ReadOperation, "wrapping_key")
+    req.Storage = storage2
+    resp, err = b2.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/backup")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"public_key": resp.Data["public_key"]}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, encAlgRSA, resp.Data["algorithm"])
+    resp = restore(map[string]interface{}{"backup": resp.Data["backup"], "force": true})
+    assert.False(t, resp.IsError(), "%v", resp.Data)
+}
+
+func TestBackupRequiresExportable(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "svc"}
+    _, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/backup")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"passphrase": "secret"}
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.True(t, resp.IsError())
+}
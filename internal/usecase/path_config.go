@@ -0,0 +1,187 @@
+// internal/usecase/path_config.go
+
+package usecase
+
+import (
+    "context"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+// walletSettingsFields returns the fields selecting the wallet contract of new keys.
+func walletSettingsFields(defaults string) map[string]*framework.FieldSchema {
+    return map[string]*framework.FieldSchema{
+        "wallet_version": {
+            Type:          framework.TypeString,
+            Description:   "Wallet contract version (v3R1, v3R2, v4R1, v4R2, v5R1)" + defaults,
+            AllowedValues: []interface{}{"v3R1", "v3R2", "v4R1", "v4R2", "v5R1"},
+        },
+        "network": {
+            Type:          framework.TypeString,
+            Description:   "Network of the wallet: mainnet or testnet" + defaults,
+            AllowedValues: []interface{}{networkMainnet, networkTestnet},
+        },
+        "workchain": {
+            Type:        framework.TypeInt,
+            Description: "Workchain of the wallet: 0 or -1" + defaults,
+        },
+    }
+}
+
+func pathConfig(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern: "config",
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.ReadOperation:   &framework.PathOperation{Callback: b.readConfig},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.writeConfig},
+        },
+        HelpSynopsis: "Configure mount-level defaults and limits.",
+        HelpDescription: `
+GET  → the current configuration.
+POST → update the given fields only.
+wallet_version, network and workchain are the defaults of new keys and can be overridden
+when creating a key‑manager. max_batch_size caps the messages signed in one transaction,
+max_key_managers and max_keys_per_manager cap key creation; 0 means no limit.
+history_retention is how long signing history is kept before the periodic tidy deletes it; 0 keeps it.
+idempotency_ttl is how long the response of a txn request is kept for retries with its idempotency_key.
+default_message_lifetime fills in valid_until when a txn request omits it and max_message_lifetime
+rejects requests with a later valid_until; key‑managers may tighten both.
+        `,
+        Fields: mergeFields(walletSettingsFields(" used for new keys."), map[string]*framework.FieldSchema{
+            "max_batch_size": {
+                Type:        framework.TypeInt,
+                Description: "Maximum number of messages in one signed transaction. 0 allows as many as the wallet accepts.",
+            },
+            "max_key_managers": {
+                Type:        framework.TypeInt,
+                Description: "Maximum number of key‑managers on this mount. 0 for no limit.",
+            },
+            "max_keys_per_manager": {
+                Type:        framework.TypeInt,
+                Description: "Maximum number of key versions per key‑manager. 0 for no limit.",
+            },
+            "history_retention": {
+                Type:        framework.TypeDurationSecond,
+                Description: "How long signing history is kept, e.g. 2160h. 0 keeps it forever.",
+            },
+            "idempotency_ttl": {
+                Type:        framework.TypeDurationSecond,
+                Description: "How long txn responses are kept for retries with the same idempotency_key. Defaults to 24h.",
+            },
+            "default_message_lifetime": {
+                Type:        framework.TypeDurationSecond,
+                Description: "Lifetime of signed messages without valid_until. Defaults to 3m.",
+            },
+            "max_message_lifetime": {
+                Type:        framework.TypeDurationSecond,
+                Description: "Longest lifetime a valid_until may ask for. 0 for no limit.",
+            },
+        }),
+    }
+}
+
+func (b *Backend) readConfig(
+    ctx context.Context,
+    req *logical.Request,
+    _ *framework.FieldData,
+) (*logical.Response, error) {
+    cfg, err := b.config(ctx, req.Storage)
+    if err != nil {
+        return nil, err
+    }
+    return &logical.Response{Data: cfg.responseData()}, nil
+}
+
+func (b *Backend) writeConfig(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    cfg, err := b.config(ctx, req.Storage)
+    if err != nil {
+        return nil, err
+    }
+    if v, ok := data.GetOk("wallet_version"); ok {
+        cfg.WalletVersion = v.(string)
+    }
+    if v, ok := data.GetOk("network"); ok {
+        cfg.Network = v.(string)
+    }
+    if v, ok := data.GetOk("workchain"); ok {
+        cfg.Workchain = v.(int)
+    }
+    for field, dst := range map[string]*int{
+        "max_batch_size":       &cfg.MaxBatchSize,
+        "max_key_managers":     &cfg.MaxKeyManagers,
+        "max_keys_per_manager": &cfg.MaxKeysPerManager,
+    } {
+        if v, ok := data.GetOk(field); ok {
+            if v.(int) < 0 {
+                return logical.ErrorResponse("%s must not be negative", field), nil
+            }
+            *dst = v.(int)
+        }
+    }
+    if v, ok := data.GetOk("history_retention"); ok {
+        if v.(int) < 0 {
+            return logical.ErrorResponse("history_retention must not be negative"), nil
+        }
+        cfg.HistoryRetention = time.Duration(v.(int)) * time.Second
+    }
+    if v, ok := data.GetOk("idempotency_ttl"); ok {
+        if v.(int) <= 0 {
+            return logical.ErrorResponse("idempotency_ttl must be positive"), nil
+        }
+        cfg.IdempotencyTTL = time.Duration(v.(int)) * time.Second
+    }
+    if v, ok := data.GetOk("default_message_lifetime"); ok {
+        if v.(int) <= 0 {
+            return logical.ErrorResponse("default_message_lifetime must be positive"), nil
+        }
+        cfg.DefaultMessageLifetime = time.Duration(v.(int)) * time.Second
+    }
+    if v, ok := data.GetOk("max_message_lifetime"); ok {
+        if v.(int) < 0 {
+            return logical.ErrorResponse("max_message_lifetime must not be negative"), nil
+        }
+        cfg.MaxMessageLifetime = time.Duration(v.(int)) * time.Second
+    }
+    if cfg.MaxMessageLifetime > 0 && cfg.DefaultMessageLifetime > cfg.MaxMessageLifetime {
+        return logical.ErrorResponse("default_message_lifetime must not exceed max_message_lifetime"), nil
+    }
+    ws, err := cfg.walletSettings()
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    if err := ws.validate(); err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    entry, err := logical.StorageEntryJSON(configPath, cfg)
+    if err != nil {
+        return nil, err
+    }
+    if err := req.Storage.Put(ctx, entry); err != nil {
+        b.Logger().Error("Failed to store config", "error", err)
+        return nil, err
+    }
+    return &logical.Response{Data: cfg.responseData()}, nil
+}
+
+func (c *backendConfig) responseData() map[string]interface{} {
+    return map[string]interface{}{
+        "wallet_version":       c.WalletVersion,
+        "network":              c.Network,
+        "workchain":            c.Workchain,
+        "max_batch_size":       c.MaxBatchSize,
+        "max_key_managers":     c.MaxKeyManagers,
+        "max_keys_per_manager": c.MaxKeysPerManager,
+        "history_retention":    int64(c.HistoryRetention.Seconds()),
+        "idempotency_ttl":      int64(c.IdempotencyTTL.Seconds()),
+
+        "default_message_lifetime": int64(c.DefaultMessageLifetime.Seconds()),
+        "max_message_lifetime":     int64(c.MaxMessageLifetime.Seconds()),
+    }
+}
@@ -0,0 +1,121 @@
+// internal/usecase/path_config_test.go
+
+package usecase
+
+import (
+    "context"
+    "encoding/base64"
+    "encoding/hex"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+func TestConfigDefaults(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.ReadOperation, "config")
+    req.Storage = storage
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, "v4R2", resp.Data["wallet_version"])
+    assert.Equal(t, networkMainnet, resp.Data["network"])
+
+    req = logical.TestRequest(t, logical.UpdateOperation, "config")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"workchain": 5}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.True(t, resp.IsError())
+
+    req.Data = map[string]interface{}{"wallet_version": "v5R1", "network": networkTestnet}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+
+    // New keys use the mount defaults
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "svc", "privateKey": testSeed}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, "v5R1", resp.Data["wallet_version"])
+    assert.Equal(t, networkTestnet, resp.Data["network"])
+    pub, err := hex.DecodeString(resp.Data["public_key"].(string))
+    require.NoError(t, err)
+    globalID := int32(wallet.TestnetGlobalID)
+    want, err := wallet.GenerateWalletAddress(pub, wallet.V5R1, &globalID, 0, nil)
+    require.NoError(t, err)
+    assert.Equal(t, want.ToHuman(true, true), resp.Data["address"])
+
+    // ...unless overridden per request
+    req.Data = map[string]interface{}{
+        "serviceName":    "svc",
+        "privateKey":     testSeed,
+        "wallet_version": "v4R2",
+        "network":        networkMainnet,
+        "workchain":      -1,
+    }
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, "v4R2", resp.Data["wallet_version"])
+    id, err := ton.ParseAccountID(resp.Data["address"].(string))
+    require.NoError(t, err)
+    assert.Equal(t, int32(-1), id.Workchain)
+
+    // V5 key signs for its own wallet
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/txn/ton/transfer")
+    req.Storage = storage
+    req.Data = map[string]interface{}{
+        "version":     1,
+        "destination": testDestination,
+        "amount":      "1000",
+        "seqno":       1,
+    }
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    bocBytes, err := base64.StdEncoding.DecodeString(resp.Data["signed_boc"].(string))
+    require.NoError(t, err)
+    cells, err := boc.DeserializeBoc(bocBytes)
+    require.NoError(t, err)
+    require.NoError(t, wallet.VerifySignature(wallet.V5R1, cells[0], pub))
+}
+
+func TestConfigLimits(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "config")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"max_batch_size": 2, "max_key_managers": 1, "max_keys_per_manager": 1}
+    _, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+
+    create := func(svc string) *logical.Response {
+        return doRequest(t, b, storage, logical.UpdateOperation, "key-managers", "", map[string]interface{}{"serviceName": svc})
+    }
+    assert.False(t, create("svc").IsError())
+    assert.True(t, create("svc").IsError(), "max_keys_per_manager")
+    assert.True(t, create("other").IsError(), "max_key_managers")
+
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/rotate")
+    req.Storage = storage
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.True(t, resp.IsError())
+
+    transfer := func(n int) *logical.Response {
+        msgs := make([]interface{}, n)
+        for i := range msgs {
+            msgs[i] = map[string]interface{}{"destination": testDestination, "amount": "1000"}
+        }
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/ton/transfer", "", map[string]interface{}{"messages": msgs, "seqno": 1})
+    }
+    assert.False(t, transfer(2).IsError())
+    assert.True(t, transfer(3).IsError())
+}
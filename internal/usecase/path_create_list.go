@@ -6,12 +6,20 @@ import (
     "context"
     "crypto/ed25519"
     "crypto/rand"
+    "encoding/base64"
     "encoding/hex"
     "fmt"
     "regexp"
+    "strings"
 
     "github.com/hashicorp/vault/sdk/framework"
     "github.com/hashicorp/vault/sdk/logical"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+const (
+    wrappedKeySeed     = "seed"
+    wrappedKeyMnemonic = "mnemonic"
 )
 
 // pathCreateAndList defines the endpoints for creating/importing
@@ -28,8 +36,8 @@ func pathCreateAndList(b *Backend) *framework.Path {
             },
         },
         HelpSynopsis:    "Create or list TON key‑managers",
-        HelpDescription: "POST to import or generate a TON ed25519 key; LIST to enumerate all services.",
-        Fields: map[string]*framework.FieldSchema{
+        HelpDescription: "POST to import (privateKey, or wrapped_key from the wrapping_key flow) or generate a TON ed25519 key; LIST to enumerate all services.",
+        Fields: mergeFields(map[string]*framework.FieldSchema{
             "serviceName": {
                 Type:        framework.TypeString,
                 Description: "Identifier for the key‑manager (e.g. your service name).",
@@ -39,7 +47,26 @@ func pathCreateAndList(b *Backend) *framework.Path {
                 Description: "(Optional) Hex-encoded 32-byte ed25519 seed. If omitted, a new random key is generated.",
                 Default:     "",
             },
-        },
+            "wrapped_key": {
+                Type:        framework.TypeString,
+                Description: "(Optional) Base64 seed or mnemonic wrapped with the mount wrapping_key (RSA-OAEP + AES-KWP). Use instead of privateKey.",
+            },
+            "wrapped_key_type": {
+                Type:          framework.TypeString,
+                Description:   "(Optional) What wrapped_key contains: seed (32 raw bytes) or mnemonic (TON mnemonic words).",
+                Default:       wrappedKeySeed,
+                AllowedValues: []interface{}{wrappedKeySeed, wrappedKeyMnemonic},
+            },
+            "exportable": {
+                Type:        framework.TypeBool,
+                Description: "(Optional) Allow the key to be exported encrypted through key-managers/:name/export. Fixed at creation.",
+            },
+            "label": {
+                Type:        framework.TypeString,
+                Description: "(Optional) LIST only key‑managers carrying this label.",
+            },
+        }, labelsFields("", "new key pair"), labelsFields("service_", "key‑manager"), escrowFields(),
+            walletSettingsFields(" of the new key. Defaults to the mount config.")),
     }
 }
 
@@ -53,7 +80,29 @@ func (b *Backend) listKeyManagers(
         b.Logger().Error("Failed to list key-managers", "error", err)
         return nil, err
     }
-    return logical.ListResponse(services), nil
+    label := data.Get("label").(string)
+    if label == "" {
+        return logical.ListResponse(services), nil
+    }
+
+    // filter by key‑manager label
+    matched := make([]string, 0, len(services))
+    keyInfo := make(map[string]interface{})
+    for _, svc := range services {
+        km, err := b.retrieveKeyManager(ctx, req, svc)
+        if err != nil {
+            return nil, err
+        }
+        if km == nil || !containsString(km.Labels, label) {
+            continue
+        }
+        matched = append(matched, svc)
+        keyInfo[svc] = map[string]interface{}{
+            "labels":   km.Labels,
+            "metadata": km.Metadata,
+        }
+    }
+    return logical.ListResponseWithInfo(matched, keyInfo), nil
 }
 
 func (b *Backend) createKeyManager(
@@ -71,19 +120,64 @@ func (b *Backend) createKeyManager(
     if !ok {
         return nil, fmt.Errorf("privateKey must be a hex string")
     }
+    labels, _, err := parseLabels(data, "labels")
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    metadata, _, err := parseMetadata(data, "metadata")
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    svcLabels, setSvcLabels, err := parseLabels(data, "service_labels")
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    svcMetadata, setSvcMetadata, err := parseMetadata(data, "service_metadata")
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    cfg, err := b.config(ctx, req.Storage)
+    if err != nil {
+        return nil, err
+    }
+    ws, err := cfg.walletSettingsFromRequest(data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
 
     // retrieve or init KeyManager
     km, err := b.retrieveKeyManager(ctx, req, svc)
     if err != nil {
         return nil, err
     }
-    if km == nil {
+    isNew := km == nil
+    if isNew {
         km = &KeyManager{ServiceName: svc}
+    } else if km.versioned() {
+        return logical.ErrorResponse("key-manager %q is versioned by rotation; create other addresses in another key-manager", svc), nil
+    }
+    if err := b.checkKeyLimits(ctx, req, cfg, km, isNew); err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    if setSvcLabels {
+        km.Labels = svcLabels
+    }
+    if setSvcMetadata {
+        km.Metadata = svcMetadata
     }
 
     // generate or import ed25519 key
     var seed []byte
-    if seedHex != "" {
+    switch wrapped := data.Get("wrapped_key").(string); {
+    case wrapped != "" && seedHex != "":
+        return nil, fmt.Errorf("privateKey and wrapped_key are mutually exclusive")
+    case wrapped != "":
+        seed, err = b.unwrapSeed(ctx, req, wrapped, data.Get("wrapped_key_type").(string))
+        if err != nil {
+            return nil, err
+        }
+    case seedHex != "":
         re := regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
         if re.FindString(seedHex) == "" {
             return nil, fmt.Errorf("privateKey must be 32-byte hex")
@@ -92,44 +186,96 @@ func (b *Backend) createKeyManager(
         if err != nil {
             return nil, fmt.Errorf("invalid privateKey hex: %w", err)
         }
-    } else {
+    default:
         seed = make([]byte, ed25519.SeedSize)
         if _, err := rand.Read(seed); err != nil {
             return nil, fmt.Errorf("failed to generate seed: %w", err)
         }
     }
-    // derive key pair
-    priv := ed25519.NewKeyFromSeed(seed)       // 64-byte private key
-    pub := priv.Public().(ed25519.PublicKey)   // 32-byte public key
-    defer zeroSeed(seed)                       // wipe seed from memory
+    defer zeroSeed(seed) // wipe seed from memory
 
-    // derive TON address (implement in utils.go)
-    addr := deriveTonAddress(pub)
+    kp, err := newKeyPair(seed, ws)
+    if err != nil {
+        return nil, err
+    }
+    kp.Labels = labels
+    kp.Metadata = metadata
+    kp.Exportable = data.Get("exportable").(bool)
+    km.addKeyPair(kp)
+    if km.CurrentVersion == 0 {
+        km.CurrentVersion = kp.ID
+    }
 
-    kp := &KeyPair{
-        PrivateKey: hex.EncodeToString(seed),
-        PublicKey:  hex.EncodeToString(pub),
-        Address:    addr,
+    // optional escrow, before anything is stored
+    var escrowShares []map[string]interface{}
+    if escrowKeys := data.Get("escrow_public_keys").([]string); len(escrowKeys) > 0 {
+        escrowShares, err = escrowSeed(km.ServiceName, kp, escrowKeys, data.Get("escrow_threshold").(int))
+        if err != nil {
+            return logical.ErrorResponse(err.Error()), nil
+        }
     }
-    km.KeyPairs = append(km.KeyPairs, kp)
 
     // store back
-    entry, _ := logical.StorageEntryJSON(
-        fmt.Sprintf("key-managers/%s", svc),
-        km,
-    )
-    if err := req.Storage.Put(ctx, entry); err != nil {
-        b.Logger().Error("Failed to store key-manager", "error", err)
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    if err := b.indexKeyPair(ctx, req.Storage, km.ServiceName, kp); err != nil {
         return nil, err
     }
 
-    return &logical.Response{
+    resp := &logical.Response{
         Data: map[string]interface{}{
-            "service_name": km.ServiceName,
-            "address":      kp.Address,
-            "public_key":   kp.PublicKey,
+            "service_name":   km.ServiceName,
+            "key_id":         kp.ID,
+            "address":        kp.Address,
+            "public_key":     kp.PublicKey,
+            "wallet_version": kp.WalletVersion,
+            "network":        kp.Network,
+            "workchain":      kp.Workchain,
+            "exportable":     kp.Exportable,
+            "labels":         kp.Labels,
+            "metadata":       kp.Metadata,
         },
-    }, nil
+    }
+    if escrowShares != nil {
+        resp.Data["escrow_shares"] = escrowShares
+    }
+    return resp, nil
+}
+
+// unwrapSeed decrypts a wrapped_key with the mount wrapping key and returns the ed25519 seed.
+func (b *Backend) unwrapSeed(ctx context.Context, req *logical.Request, wrapped, kind string) ([]byte, error) {
+    ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimSpace(wrapped))
+    if err != nil {
+        return nil, fmt.Errorf("wrapped_key must be base64: %w", err)
+    }
+    key, err := b.wrappingKey(ctx, req.Storage)
+    if err != nil {
+        return nil, err
+    }
+    plaintext, err := unwrapRSAKWP(key, ciphertext)
+    if err != nil {
+        return nil, err
+    }
+
+    switch kind {
+    case wrappedKeySeed:
+        if len(plaintext) != ed25519.SeedSize {
+            zeroSeed(plaintext)
+            return nil, fmt.Errorf("wrapped seed must be %d bytes, got %d", ed25519.SeedSize, len(plaintext))
+        }
+        return plaintext, nil
+    case wrappedKeyMnemonic:
+        defer zeroSeed(plaintext)
+        priv, err := wallet.SeedToPrivateKey(strings.Join(strings.Fields(string(plaintext)), " "))
+        if err != nil {
+            return nil, fmt.Errorf("invalid wrapped mnemonic: %w", err)
+        }
+        defer zeroSeed(priv)
+        return append([]byte(nil), priv.Seed()...), nil
+    default:
+        return nil, fmt.Errorf("unsupported wrapped_key_type %q", kind)
+    }
 }
 
 // zeroSeed overwrites the seed bytes in memory.
@@ -0,0 +1,94 @@
+// internal/usecase/path_destinations.go
+
+package usecase
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+func pathDestinations(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/destinations",
+        ExistenceCheck: b.pathExistenceCheck,
+        HelpSynopsis:   "Manage the destination allowlist and denylist of a key‑manager",
+        HelpDescription: `
+GET     — return the allowed and denied destinations in raw form
+POST    — replace allowed_destinations and/or denied_destinations; pass an empty list to clear one
+Addresses may be given in any friendly or raw form. Every transfer checks the address it sends to
+and, for jettons and NFTs, the final recipient: denied addresses are refused and, when the
+allowlist is not empty, so is any address missing from it. A jetton transfer is sent to the
+sender's jetton wallet, so allowlists must also list the jetton wallets in use.
+        `,
+        Fields: map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+            "allowed_destinations": {
+                Type:        framework.TypeCommaStringSlice,
+                Description: "Addresses transfers may be sent to. Empty allows any address not denied.",
+            },
+            "denied_destinations": {
+                Type:        framework.TypeCommaStringSlice,
+                Description: "Addresses transfers may never be sent to.",
+            },
+        },
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.ReadOperation:   &framework.PathOperation{Callback: b.readDestinations},
+            logical.CreateOperation: &framework.PathOperation{Callback: b.writeDestinations},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.writeDestinations},
+        },
+    }
+}
+
+// readDestinations handles GET key-managers/{name}/destinations
+func (b *Backend) readDestinations(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "service_name":         km.ServiceName,
+            "allowed_destinations": km.AllowedDestinations,
+            "denied_destinations":  km.DeniedDestinations,
+        },
+    }, nil
+}
+
+// writeDestinations handles POST key-managers/{name}/destinations
+func (b *Backend) writeDestinations(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    for field, dst := range map[string]*[]string{
+        "allowed_destinations": &km.AllowedDestinations,
+        "denied_destinations":  &km.DeniedDestinations,
+    } {
+        raw, ok := data.GetOk(field)
+        if !ok {
+            continue
+        }
+        addrs, err := normalizeAddresses(raw.([]string))
+        if err != nil {
+            return logical.ErrorResponse("%s: %s", field, err), nil
+        }
+        *dst = addrs
+    }
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return b.readDestinations(ctx, req, data)
+}
@@ -0,0 +1,79 @@
+// internal/usecase/path_destinations_test.go
+
+package usecase
+
+import (
+    "context"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/tonkeeper/tongo/ton"
+)
+
+func TestDestinationLists(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    hot := ton.MustParseAccountID(testDestination)
+    jettonWallet := "0:3333333333333333333333333333333333333333333333333333333333333333"
+    other := "0:4444444444444444444444444444444444444444444444444444444444444444"
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "sweep"}
+    _, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+
+    // Friendly and raw forms compare equal
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/sweep/destinations")
+    req.Storage = storage
+    req.Data = map[string]interface{}{
+        "allowed_destinations": []string{hot.ToHuman(true, false), jettonWallet},
+        "denied_destinations":  []string{jettonWallet},
+    }
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, []string{testDestination, jettonWallet}, resp.Data["allowed_destinations"])
+
+    assert.False(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/sweep/txn/ton/transfer", "", map[string]interface{}{"destination": testDestination, "amount": "1"}).IsError())
+    assert.True(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/sweep/txn/ton/transfer", "", map[string]interface{}{"destination": other, "amount": "1"}).IsError())
+
+    // Jetton transfers check the jetton wallet (denied here) and the recipient
+    resp = doRequest(t, b, storage, logical.CreateOperation, "key-managers/sweep/txn/jetton/transfer", "", map[string]interface{}{
+        "jettonWallet": jettonWallet, "destination": testDestination, "amount": "1",
+    })
+    assert.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "denied")
+
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/sweep/destinations")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"denied_destinations": []string{}}
+    _, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.False(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/sweep/txn/jetton/transfer", "", map[string]interface{}{
+        "jettonWallet": jettonWallet, "destination": testDestination, "amount": "1",
+    }).IsError())
+    assert.True(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/sweep/txn/jetton/transfer", "", map[string]interface{}{
+        "jettonWallet": jettonWallet, "destination": other, "amount": "1",
+    }).IsError())
+
+    // the excess may return to the wallet itself, or to an allowed address only
+    assert.False(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/sweep/txn/jetton/transfer", "", map[string]interface{}{
+        "jettonWallet": jettonWallet, "destination": testDestination, "amount": "1", "response_destination": testDestination,
+    }).IsError())
+    resp = doRequest(t, b, storage, logical.CreateOperation, "key-managers/sweep/txn/jetton/transfer", "", map[string]interface{}{
+        "jettonWallet": jettonWallet, "destination": testDestination, "amount": "1", "response_destination": other,
+    })
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), other)
+
+    // NFT transfers check the item and the new owner
+    assert.False(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/sweep/txn/nft/transfer", "", map[string]interface{}{
+        "nft_address": jettonWallet, "new_owner": testDestination,
+    }).IsError())
+    assert.True(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/sweep/txn/nft/transfer", "", map[string]interface{}{
+        "nft_address": jettonWallet, "new_owner": other,
+    }).IsError())
+}
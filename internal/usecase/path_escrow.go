@@ -0,0 +1,172 @@
+// internal/usecase/path_escrow.go
+
+package usecase
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+func pathEscrow(b *Backend) []*framework.Path {
+    return []*framework.Path{
+        {
+            Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/escrow",
+            ExistenceCheck: b.pathExistenceCheck,
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.CreateOperation: &framework.PathOperation{Callback: b.escrowKey},
+                logical.UpdateOperation: &framework.PathOperation{Callback: b.escrowKey},
+            },
+            HelpSynopsis: "Split the seed of an existing key into encrypted Shamir shares.",
+            HelpDescription: `
+POST name, version, escrow_public_keys, escrow_threshold → escrow_shares.
+The seed is split into one share per public key, any escrow_threshold of which recover it.
+Each share is encrypted to its officer's key and returned once; nothing is stored.
+Only exportable keys can be escrowed after creation.
+            `,
+            Fields: mergeFields(map[string]*framework.FieldSchema{
+                "name": {Type: framework.TypeString},
+                "version": {
+                    Type:        framework.TypeInt,
+                    Description: "(Optional) Key version to escrow. Defaults to the current version.",
+                },
+            }, escrowFields()),
+        },
+        {
+            Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/recover",
+            ExistenceCheck: b.pathExistenceCheck,
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.CreateOperation: &framework.PathOperation{Callback: b.recoverKey},
+                logical.UpdateOperation: &framework.PathOperation{Callback: b.recoverKey},
+            },
+            HelpSynopsis: "Rebuild a key from decrypted escrow shares.",
+            HelpDescription: `
+POST name, shares → recovered key.
+shares are the decrypted escrow share documents. The recovered key is added to the key‑manager,
+which is created if it does not exist, and becomes its current version. It keeps the wallet
+contract recorded in the shares.
+            `,
+            Fields: map[string]*framework.FieldSchema{
+                "name": {Type: framework.TypeString},
+                "shares": {
+                    Type:        framework.TypeStringSlice,
+                    Description: "Decrypted escrow shares, at least the escrow threshold of them.",
+                },
+                "exportable": {
+                    Type:        framework.TypeBool,
+                    Description: "(Optional) Mark the recovered key exportable.",
+                },
+            },
+        },
+    }
+}
+
+func (b *Backend) escrowKey(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    version := data.Get("version").(int)
+    if version == 0 {
+        version = km.CurrentVersion
+    }
+    kp := km.keyPairByID(version)
+    if kp == nil {
+        return logical.ErrorResponse("key version %d not found in key-manager %q", version, name), nil
+    }
+    if !kp.Exportable {
+        return logical.ErrorResponse("key version %d of key-manager %q is not exportable", version, name), nil
+    }
+
+    shares, err := escrowSeed(km.ServiceName, kp, data.Get("escrow_public_keys").([]string), data.Get("escrow_threshold").(int))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    b.Logger().Info("Key escrowed", "name", name, "version", version, "shares", len(shares))
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "service_name":  km.ServiceName,
+            "version":       kp.ID,
+            "public_key":    kp.PublicKey,
+            "escrow_shares": shares,
+        },
+    }, nil
+}
+
+func (b *Backend) recoverKey(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+
+    seed, share, err := combineEscrowShares(data.Get("shares").([]string))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    defer zeroSeed(seed)
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil {
+        return nil, err
+    }
+    isNew := km == nil
+    if isNew {
+        km = &KeyManager{ServiceName: name}
+    }
+    cfg, err := b.config(ctx, req.Storage)
+    if err != nil {
+        return nil, err
+    }
+    if err := b.checkKeyLimits(ctx, req, cfg, km, isNew); err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    ws, err := share.walletSettings(cfg)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    for _, existing := range km.KeyPairs {
+        if existing.PublicKey == share.PublicKey {
+            return logical.ErrorResponse("key-manager %q already holds this key as version %d", name, existing.ID), nil
+        }
+    }
+
+    kp, err := newKeyPair(seed, ws)
+    if err != nil {
+        return nil, err
+    }
+    kp.Exportable = data.Get("exportable").(bool)
+    if isNew {
+        km.addKeyPair(kp)
+        km.CurrentVersion = kp.ID
+    } else if err := km.rotate(kp); err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    if err := b.indexKeyPair(ctx, req.Storage, km.ServiceName, kp); err != nil {
+        return nil, err
+    }
+
+    b.Logger().Info("Key recovered from escrow", "name", name, "version", kp.ID,
+        "escrowed_from", share.ServiceName, "escrowed_version", share.KeyVersion)
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "service_name": km.ServiceName,
+            "version":      kp.ID,
+            "address":      kp.Address,
+            "public_key":   kp.PublicKey,
+        },
+    }, nil
+}
@@ -0,0 +1,87 @@
+// internal/usecase/path_escrow_test.go
+
+package usecase
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/base64"
+    "encoding/hex"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "golang.org/x/crypto/nacl/box"
+)
+
+func TestEscrowAndRecover(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    type officer struct{ pub, priv *[32]byte }
+    officers := make([]officer, 3)
+    pubs := make([]string, 3)
+    for i := range officers {
+        pub, priv, err := box.GenerateKey(rand.Reader)
+        require.NoError(t, err)
+        officers[i] = officer{pub, priv}
+        pubs[i] = hex.EncodeToString(pub[:])
+    }
+    decrypt := func(shares interface{}, i int) string {
+        share := shares.([]map[string]interface{})[i]
+        ciphertext, err := base64.StdEncoding.DecodeString(share["ciphertext"].(string))
+        require.NoError(t, err)
+        plaintext, ok := box.OpenAnonymous(nil, ciphertext, officers[i].pub, officers[i].priv)
+        require.True(t, ok)
+        return string(plaintext)
+    }
+
+    // Escrow on creation, 2 of 3
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{
+        "serviceName":        "treasury",
+        "escrow_public_keys": pubs,
+        "escrow_threshold":   2,
+    }
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    publicKey := resp.Data["public_key"]
+    shares := resp.Data["escrow_shares"]
+    require.Len(t, shares, 3)
+
+    // Threshold above the number of officers is rejected
+    req.Data = map[string]interface{}{
+        "serviceName":        "other",
+        "escrow_public_keys": pubs,
+        "escrow_threshold":   4,
+    }
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.True(t, resp.IsError())
+
+    // Recover into another mount from two shares
+    b2, storage2 := newTestBackend(t)
+    recoverWith := func(shares ...string) *logical.Response {
+        return doRequest(t, b2, storage2, logical.CreateOperation, "key-managers/treasury/recover", "", map[string]interface{}{"shares": shares})
+    }
+    resp = recoverWith(decrypt(shares, 2))
+    assert.True(t, resp.IsError())
+
+    resp = recoverWith(decrypt(shares, 0), decrypt(shares, 2))
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, publicKey, resp.Data["public_key"])
+    assert.Equal(t, 1, resp.Data["version"])
+
+    resp = recoverWith(decrypt(shares, 1), decrypt(shares, 2))
+    assert.True(t, resp.IsError(), "recovering the same key twice")
+
+    // Escrow of an existing key needs it to be exportable
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/treasury/escrow")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"escrow_public_keys": pubs, "escrow_threshold": 2}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.True(t, resp.IsError())
+}
@@ -0,0 +1,99 @@
+// internal/usecase/path_export.go
+
+package usecase
+
+import (
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+// exportedKey is the plaintext encrypted to the caller by the export endpoint.
+type exportedKey struct {
+    Seed      string `json:"seed"`
+    PublicKey string `json:"public_key"`
+    Address   string `json:"address"`
+}
+
+func pathExport(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/export",
+        ExistenceCheck: b.pathExistenceCheck,
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.CreateOperation: &framework.PathOperation{Callback: b.exportKey},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.exportKey},
+        },
+        HelpSynopsis: "Export an exportable key encrypted to a caller-supplied public key.",
+        HelpDescription: `
+POST name, public_key, version → algorithm, ciphertext(base64).
+Only keys created with exportable=true can be exported. The JSON {"seed","public_key","address"}
+is encrypted either with RSA-OAEP(SHA-256) + AES-256-GCM for an RSA public key, or as a
+NaCl sealed box for an X25519 public key. The seed is never returned in plaintext.
+        `,
+        Fields: map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+            "public_key": {
+                Type:        framework.TypeString,
+                Description: "PEM RSA (>= 2048 bits) or X25519 public key, or a raw X25519 key in hex/base64.",
+            },
+            "version": {
+                Type:        framework.TypeInt,
+                Description: "(Optional) Key version to export. Defaults to the current version.",
+            },
+        },
+    }
+}
+
+func (b *Backend) exportKey(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    version := data.Get("version").(int)
+    if version == 0 {
+        version = km.CurrentVersion
+    }
+    kp := km.keyPairByID(version)
+    if kp == nil {
+        return logical.ErrorResponse("key version %d not found in key-manager %q", version, name), nil
+    }
+    if !kp.Exportable {
+        return logical.ErrorResponse("key version %d of key-manager %q is not exportable", version, name), nil
+    }
+
+    plaintext, err := json.Marshal(exportedKey{
+        Seed:      kp.PrivateKey,
+        PublicKey: kp.PublicKey,
+        Address:   kp.Address,
+    })
+    if err != nil {
+        return nil, err
+    }
+    defer zeroSeed(plaintext)
+
+    alg, ciphertext, err := encryptToRecipient(data.Get("public_key").(string), plaintext)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    b.Logger().Info("Key exported", "name", name, "version", version, "algorithm", alg)
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "service_name": km.ServiceName,
+            "version":      kp.ID,
+            "address":      kp.Address,
+            "algorithm":    alg,
+            "ciphertext":   base64.StdEncoding.EncodeToString(ciphertext),
+        },
+    }, nil
+}
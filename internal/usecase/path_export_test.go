@@ -0,0 +1,75 @@
+// internal/usecase/path_export_test.go
+
+package usecase
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/base64"
+    "encoding/hex"
+    "encoding/json"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "golang.org/x/crypto/nacl/box"
+)
+
+func TestExportKey(t *testing.T) {
+    b, storage := newTestBackend(t)
+    recipientPub, recipientPriv, err := box.GenerateKey(rand.Reader)
+    require.NoError(t, err)
+
+    // Non-exportable key is refused
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "locked"}
+    _, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/locked/export")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"public_key": hex.EncodeToString(recipientPub[:])}
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.True(t, resp.IsError())
+
+    // Exportable key is encrypted to the recipient
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{
+        "serviceName": "svc",
+        "privateKey":  testSeed,
+        "exportable":  true,
+    }
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, true, resp.Data["exportable"])
+
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/export")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"public_key": base64.StdEncoding.EncodeToString(recipientPub[:])}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, encAlgX25519, resp.Data["algorithm"])
+    assert.NotContains(t, resp.Data["ciphertext"], testSeed)
+
+    ciphertext, err := base64.StdEncoding.DecodeString(resp.Data["ciphertext"].(string))
+    require.NoError(t, err)
+    plaintext, ok := box.OpenAnonymous(nil, ciphertext, recipientPub, recipientPriv)
+    require.True(t, ok)
+    var exported exportedKey
+    require.NoError(t, json.Unmarshal(plaintext, &exported))
+    assert.Equal(t, testSeed, exported.Seed)
+    assert.Equal(t, resp.Data["address"], exported.Address)
+
+    // Invalid recipient key
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/export")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"public_key": "not-a-key"}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.True(t, resp.IsError())
+}
@@ -0,0 +1,142 @@
+// internal/usecase/path_history.go
+
+package usecase
+
+import (
+    "context"
+    "sort"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+const defaultHistoryListLimit = 100
+
+func pathHistory(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:      "key-managers/" + framework.GenericNameRegex("name") + "/history/?$",
+        HelpSynopsis: "List what the keys of a key‑manager signed",
+        HelpDescription: `
+LIST    — return history entry ids, oldest first, with the entries in key_info: time, entity,
+          key, signed hash or msg_id, decoded messages and request metadata.
+          Use "after" (last id of the previous page) and "limit" to paginate, and from, to and
+          key_id to filter. History outlives deleted key‑managers until history_retention expires.
+        `,
+        Fields: map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+            "after": {
+                Type:        framework.TypeString,
+                Description: "Return only entries after this id.",
+            },
+            "limit": {
+                Type:        framework.TypeInt,
+                Description: "Maximum number of entries to return.",
+                Default:     defaultHistoryListLimit,
+            },
+            "from": {
+                Type:        framework.TypeTime,
+                Description: "Only return entries at or after this time (RFC3339 or unix seconds).",
+            },
+            "to": {
+                Type:        framework.TypeTime,
+                Description: "Only return entries before this time (RFC3339 or unix seconds).",
+            },
+            "key_id": {
+                Type:        framework.TypeInt,
+                Description: "Only return entries signed by this key version.",
+            },
+        },
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.ListOperation: &framework.PathOperation{Callback: b.listHistory},
+        },
+    }
+}
+
+// listHistory handles LIST key-managers/{name}/history
+func (b *Backend) listHistory(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    after := data.Get("after").(string)
+    limit := data.Get("limit").(int)
+    if limit <= 0 {
+        return logical.ErrorResponse("limit must be positive"), nil
+    }
+    var from, to time.Time
+    if v, ok := data.GetOk("from"); ok {
+        from = v.(time.Time)
+    }
+    if v, ok := data.GetOk("to"); ok {
+        to = v.(time.Time)
+    }
+    keyID, filterKey := data.GetOk("key_id")
+
+    prefix := historyPrefix + name + "/"
+    ids, err := req.Storage.List(ctx, prefix)
+    if err != nil {
+        return nil, err
+    }
+    sort.Strings(ids)
+
+    keys := make([]string, 0, limit)
+    infos := make(map[string]interface{}, limit)
+    for _, id := range ids {
+        if id <= after {
+            continue
+        }
+        // ids carry their time, so the range is checked before loading entries
+        at, ok := historyTime(id)
+        if !ok || (!from.IsZero() && at.Before(from)) {
+            continue
+        }
+        if !to.IsZero() && !at.Before(to) {
+            break
+        }
+        entry, err := req.Storage.Get(ctx, prefix+id)
+        if err != nil {
+            return nil, err
+        }
+        if entry == nil {
+            continue
+        }
+        e := &historyEntry{}
+        if err := entry.DecodeJSON(e); err != nil {
+            return nil, err
+        }
+        if filterKey && e.KeyID != keyID.(int) {
+            continue
+        }
+        keys = append(keys, id)
+        infos[id] = e.info()
+        if len(keys) == limit {
+            break
+        }
+    }
+    return logical.ListResponseWithInfo(keys, infos), nil
+}
+
+func (e *historyEntry) info() map[string]interface{} {
+    info := map[string]interface{}{
+        "at":           e.At.Format(time.RFC3339Nano),
+        "service_name": e.ServiceName,
+        "key_id":       e.KeyID,
+        "address":      e.Address,
+        "operation":    e.Operation,
+        "hash":         e.Hash,
+        "entity_id":    e.EntityID,
+        "request":      e.Request,
+        "seq":          e.Seq,
+        "prev_hash":    e.PrevHash,
+        "record_hash":  e.RecordHash,
+    }
+    if e.Seqno != nil {
+        info["seqno"] = *e.Seqno
+    }
+    if len(e.Messages) > 0 {
+        info["messages"] = e.Messages
+    }
+    return info
+}
@@ -0,0 +1,71 @@
+// internal/usecase/path_history_test.go
+
+package usecase
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestSigningHistory(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers", "alice", map[string]interface{}{"serviceName": "svc"}))
+    start := time.Now()
+    requireOK(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/sign", "alice", map[string]interface{}{"hash": testHash}))
+    transfer := requireOK(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/ton/transfer", "alice", map[string]interface{}{
+        "destination": testDestination, "amount": "1500000000", "seqno": 3,
+    }))
+    requireOK(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/rotate", "alice", nil))
+    requireOK(t, doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/sign", "alice", map[string]interface{}{"hash": testHash}))
+
+    list := func(data map[string]interface{}) ([]string, map[string]interface{}) {
+        resp := requireOK(t, doRequest(t, b, storage, logical.ListOperation, "key-managers/svc/history/", "alice", data))
+        keys, _ := resp.Data["keys"].([]string)
+        infos, _ := resp.Data["key_info"].(map[string]interface{})
+        return keys, infos
+    }
+    keys, infos := list(nil)
+    require.Len(t, keys, 3)
+    first := infos[keys[0]].(map[string]interface{})
+    assert.Equal(t, "sign", first["operation"])
+    assert.Equal(t, testHash, first["hash"])
+    assert.Equal(t, "alice", first["entity_id"])
+
+    second := infos[keys[1]].(map[string]interface{})
+    assert.Equal(t, "txn/ton/transfer", second["operation"])
+    assert.Equal(t, transfer.Data["msg_id"], second["hash"])
+    assert.Equal(t, uint32(3), second["seqno"])
+    msgs := second["messages"].([]map[string]interface{})
+    assert.Equal(t, testDestination, msgs[0]["destination"])
+    assert.Equal(t, "1500000000", msgs[0]["amount"])
+
+    // pagination, key and time filters
+    page, _ := list(map[string]interface{}{"limit": 2})
+    assert.Equal(t, keys[:2], page)
+    page, _ = list(map[string]interface{}{"after": keys[1]})
+    assert.Equal(t, keys[2:], page)
+    page, _ = list(map[string]interface{}{"key_id": 2})
+    assert.Equal(t, keys[2:], page)
+    page, _ = list(map[string]interface{}{"to": start.Add(-time.Minute).Format(time.RFC3339)})
+    assert.Empty(t, page)
+
+    // the periodic tidy enforces the retention
+    requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "config", "alice", map[string]interface{}{"history_retention": "1h"}))
+    req := &logical.Request{Storage: storage}
+    require.NoError(t, b.tidyHistory(context.Background(), req))
+    page, _ = list(nil)
+    assert.Len(t, page, 3)
+
+    old, err := historyID(time.Now().Add(-2 * time.Hour))
+    require.NoError(t, err)
+    require.NoError(t, storage.Put(context.Background(), &logical.StorageEntry{Key: historyPrefix + "svc/" + old, Value: []byte("{}")}))
+    require.NoError(t, b.tidyHistory(context.Background(), req))
+    page, _ = list(nil)
+    assert.Equal(t, keys, page)
+}
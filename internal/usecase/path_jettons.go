@@ -0,0 +1,167 @@
+// internal/usecase/path_jettons.go
+
+package usecase
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/tonkeeper/tongo/ton"
+)
+
+func pathJettons(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/jettons",
+        ExistenceCheck: b.pathExistenceCheck,
+        HelpSynopsis:   "Manage the jetton masters a key‑manager may send",
+        HelpDescription: `
+GET     — return the allowlisted jetton masters with the jetton wallet of the current key
+POST    — allowlist master with its decimals and jetton wallet code
+DELETE  — remove master from the allowlist
+Once a master is allowlisted, jetton transfers must pass jetton_master, the plugin computes the
+sender's jetton wallet from the master, the owner and wallet_code, and other masters are refused.
+Limits of allowlisted jettons can be set in human units.
+        `,
+        Fields: map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+            "master": {
+                Type:        framework.TypeString,
+                Description: "Address of the jetton master.",
+            },
+            "symbol": {
+                Type:        framework.TypeString,
+                Description: "(Optional) Ticker, informational only.",
+            },
+            "decimals": {
+                Type:        framework.TypeInt,
+                Description: "Decimals of the jetton, 9 for most jettons and 6 for USDT.",
+                Default:     tonDecimals,
+            },
+            "wallet_code": {
+                Type:        framework.TypeString,
+                Description: "Base64 BOC of the jetton wallet code, as returned by the master's get_jetton_data.",
+            },
+            "wallet_layout": {
+                Type:          framework.TypeString,
+                Description:   "Data layout of the jetton wallet: standard (TEP-74 reference) or stablecoin (status, balance, owner, master, e.g. USDT).",
+                Default:       jettonLayoutStandard,
+                AllowedValues: []interface{}{jettonLayoutStandard, jettonLayoutStablecoin},
+            },
+        },
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.ReadOperation:   &framework.PathOperation{Callback: b.readJettons},
+            logical.CreateOperation: &framework.PathOperation{Callback: b.writeJetton},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.writeJetton},
+            logical.DeleteOperation: &framework.PathOperation{Callback: b.deleteJetton},
+        },
+    }
+}
+
+// readJettons handles GET key-managers/{name}/jettons
+func (b *Backend) readJettons(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    var owner *ton.AccountID
+    if kp := km.keyPairByID(km.CurrentVersion); kp != nil {
+        if id, err := ton.ParseAccountID(kp.Address); err == nil {
+            owner = &id
+        }
+    }
+
+    jettons := make(map[string]interface{}, len(km.Jettons))
+    for raw, j := range km.Jettons {
+        info := map[string]interface{}{
+            "symbol":        j.Symbol,
+            "decimals":      j.Decimals,
+            "wallet_layout": j.WalletLayout,
+        }
+        if owner != nil {
+            if wallet, err := j.walletAddress(ton.MustParseAccountID(raw), *owner); err == nil {
+                info["jetton_wallet"] = wallet.ToRaw()
+            }
+        }
+        jettons[raw] = info
+    }
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "service_name": km.ServiceName,
+            "jettons":      jettons,
+        },
+    }, nil
+}
+
+// writeJetton handles POST key-managers/{name}/jettons
+func (b *Backend) writeJetton(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    master, err := normalizeAddress(data.Get("master").(string))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    j := &jettonMaster{
+        Symbol:       data.Get("symbol").(string),
+        Decimals:     data.Get("decimals").(int),
+        WalletCode:   data.Get("wallet_code").(string),
+        WalletLayout: data.Get("wallet_layout").(string),
+    }
+    if j.Decimals < 0 || j.Decimals > 255 {
+        return logical.ErrorResponse("decimals must be between 0 and 255"), nil
+    }
+    if j.WalletCode == "" {
+        return logical.ErrorResponse("wallet_code is required to compute jetton wallets"), nil
+    }
+    if _, err := j.walletAddress(ton.MustParseAccountID(master), ton.AccountID{}); err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    if km.Jettons == nil {
+        km.Jettons = make(map[string]*jettonMaster)
+    }
+    km.Jettons[master] = j
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return b.readJettons(ctx, req, data)
+}
+
+// deleteJetton handles DELETE key-managers/{name}/jettons
+func (b *Backend) deleteJetton(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    master, err := normalizeAddress(data.Get("master").(string))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    // limits and thresholds rely on the allowlist to attribute transfers to a master
+    if usage := km.jettonUsage(master); usage != "" {
+        return logical.ErrorResponse("jetton master %s still has %s, remove it first", master, usage), nil
+    }
+    delete(km.Jettons, master)
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return nil, nil
+}
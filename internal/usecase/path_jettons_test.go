@@ -0,0 +1,132 @@
+// internal/usecase/path_jettons_test.go
+
+package usecase
+
+import (
+    "context"
+    "encoding/base64"
+    "encoding/hex"
+    "math/big"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/ton"
+)
+
+func testWalletCode(t *testing.T) string {
+    code := boc.NewCell()
+    require.NoError(t, code.WriteUint(0xdeadbeef, 32))
+    raw, err := code.ToBoc()
+    require.NoError(t, err)
+    return base64.StdEncoding.EncodeToString(raw)
+}
+
+func TestJettonAllowlist(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "svc"}
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    pub, err := hex.DecodeString(resp.Data["public_key"].(string))
+    require.NoError(t, err)
+
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/svc/jettons")
+    req.Storage = storage
+    req.Data = map[string]interface{}{
+        "master":        testJettonMaster,
+        "symbol":        "USDT",
+        "decimals":      6,
+        "wallet_code":   testWalletCode(t),
+        "wallet_layout": jettonLayoutStablecoin,
+    }
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    info := resp.Data["jettons"].(map[string]interface{})[testJettonMaster].(map[string]interface{})
+    jettonWallet := info["jetton_wallet"].(string)
+    require.NotEmpty(t, jettonWallet)
+
+    sendJetton := func(data map[string]interface{}) *logical.Response {
+        fields := map[string]interface{}{"destination": testDestination, "seqno": 1}
+        for k, v := range data {
+            fields[k] = v
+        }
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/jetton/transfer", "", fields)
+    }
+
+    // other masters and foreign jetton wallets are refused
+    resp = sendJetton(map[string]interface{}{"jettonWallet": testDestination, "amount": "1"})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "jetton_master is required")
+    resp = sendJetton(map[string]interface{}{"jetton_master": testDestination, "amount": "1"})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "not allowlisted")
+    resp = sendJetton(map[string]interface{}{"jetton_master": testJettonMaster, "jettonWallet": testDestination, "amount": "1"})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "expected "+jettonWallet)
+
+    // the jetton wallet is computed when omitted
+    resp = sendJetton(map[string]interface{}{"jetton_master": testJettonMaster, "amount": "1"})
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    msgs, _ := decodeSignedTransfer(t, resp, pub)
+    require.Len(t, msgs, 1)
+    dest, err := ton.AccountIDFromTlb(msgs[0].Info.IntMsgInfo.Dest)
+    require.NoError(t, err)
+    assert.Equal(t, jettonWallet, dest.ToRaw())
+
+    // limits in human units use the decimals of the master
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/svc/limits")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"asset": testJettonMaster, "max_per_tx": "2.5", "unit": unitHuman}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    limit := resp.Data["limits"].(map[string]interface{})[testJettonMaster].(map[string]interface{})
+    assert.Equal(t, "2500000", limit["max_per_tx"])
+    assert.Equal(t, 6, limit["decimals"])
+    assert.Equal(t, "2.5", limit["human"].(map[string]interface{})["max_per_tx"])
+
+    assert.True(t, sendJetton(map[string]interface{}{"jetton_master": testJettonMaster, "amount": "2500001"}).IsError())
+    assert.False(t, sendJetton(map[string]interface{}{"jetton_master": testJettonMaster, "amount": "2500000"}).IsError())
+
+    // unknown decimals cannot be used in human units
+    req.Data = map[string]interface{}{"asset": testDestination, "max_per_tx": "1", "unit": unitHuman}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.True(t, resp.IsError())
+
+    // thresholds only name allowlisted masters, which stay allowlisted while limited
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/svc/approvals")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"required_approvals": 1, "thresholds": map[string]interface{}{testDestination: "1"}, "ttl": "1h"}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "not allowlisted")
+
+    req = logical.TestRequest(t, logical.DeleteOperation, "key-managers/svc/jettons")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"master": testJettonMaster}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "spending limit")
+}
+
+func TestUnits(t *testing.T) {
+    v, err := parseUnits("1.5", 9, "amount")
+    require.NoError(t, err)
+    assert.Equal(t, "1500000000", v.String())
+    _, err = parseUnits("0.0000000001", 9, "amount")
+    assert.Error(t, err)
+    _, err = parseUnits("-1", 9, "amount")
+    assert.Error(t, err)
+
+    assert.Equal(t, "0.000001", formatUnits(big.NewInt(1), 6))
+    assert.Equal(t, "12", formatUnits(big.NewInt(12000000), 6))
+}
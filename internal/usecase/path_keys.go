@@ -0,0 +1,284 @@
+// internal/usecase/path_keys.go
+
+package usecase
+
+import (
+    "context"
+    "fmt"
+    "strconv"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+    keyStatusEnabled  = "enabled"
+    keyStatusDisabled = "disabled"
+
+    defaultKeysListLimit = 100
+)
+
+// pathListKeys defines the paginated key listing inside a single key‑manager.
+func pathListKeys(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:      "key-managers/" + framework.GenericNameRegex("name") + "/keys/?$",
+        HelpSynopsis: "List the keys of a TON key‑manager page by page",
+        HelpDescription: `
+LIST    — return key ids of the key‑manager with per‑key metadata in key_info.
+          Use "after" (last key id of the previous page) and "limit" to paginate,
+          and wallet_version, label, status, created_after, created_before to filter.
+        `,
+        Fields: map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+            "after": {
+                Type:        framework.TypeString,
+                Description: "Return only keys with an id greater than this one.",
+            },
+            "limit": {
+                Type:        framework.TypeInt,
+                Description: "Maximum number of keys to return.",
+                Default:     defaultKeysListLimit,
+            },
+            "wallet_version": {
+                Type:        framework.TypeString,
+                Description: "Only return keys deriving this wallet version (e.g. v4R2).",
+            },
+            "label": {
+                Type:        framework.TypeString,
+                Description: "Only return keys carrying this label.",
+            },
+            "status": {
+                Type:          framework.TypeString,
+                Description:   "Only return keys in this status.",
+                AllowedValues: []interface{}{keyStatusEnabled, keyStatusDisabled},
+            },
+            "created_after": {
+                Type:        framework.TypeTime,
+                Description: "Only return keys created at or after this time (RFC3339 or unix seconds).",
+            },
+            "created_before": {
+                Type:        framework.TypeTime,
+                Description: "Only return keys created before this time (RFC3339 or unix seconds).",
+            },
+        },
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.ListOperation: &framework.PathOperation{Callback: b.listKeys},
+        },
+    }
+}
+
+// pathReadUpdateKey defines the endpoints for a single key of a key‑manager.
+func pathReadUpdateKey(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/keys/(?P<key_id>[0-9]+)",
+        ExistenceCheck: b.keyExistenceCheck,
+        HelpSynopsis:   "Read or update a single key of a TON key‑manager",
+        HelpDescription: `
+GET     — return the key metadata (address, public key, wallet version, status, labels, metadata)
+POST    — replace the key labels and/or metadata, or enable/disable the key for signing
+        `,
+        Fields: mergeFields(map[string]*framework.FieldSchema{
+            "name":   {Type: framework.TypeString},
+            "key_id": {Type: framework.TypeInt, Description: "Id of the key inside the key‑manager."},
+            "disabled": {
+                Type:        framework.TypeBool,
+                Description: "(Optional) Disable the key for signing. A disabled key stays readable and keeps verifying.",
+            },
+        }, labelsFields("", "key pair")),
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.ReadOperation:   &framework.PathOperation{Callback: b.readKey},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.updateKey},
+        },
+    }
+}
+
+// keyExistenceCheck maps POST on an existing key to an update.
+func (b *Backend) keyExistenceCheck(ctx context.Context, req *logical.Request, data *framework.FieldData) (bool, error) {
+    km, err := b.retrieveKeyManager(ctx, req, data.Get("name").(string))
+    if err != nil {
+        return false, fmt.Errorf("existence check failed: %w", err)
+    }
+    return km != nil && km.keyPairByID(data.Get("key_id").(int)) != nil, nil
+}
+
+// readKey handles GET key‑managers/{name}/keys/{key_id}
+func (b *Backend) readKey(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    id := data.Get("key_id").(int)
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil {
+        b.Logger().Error("Failed to retrieve key‑manager", "name", name, "error", err)
+        return nil, err
+    }
+    if km == nil {
+        return nil, fmt.Errorf("key‑manager %q not found", name)
+    }
+    kp := km.keyPairByID(id)
+    if kp == nil {
+        return nil, fmt.Errorf("key %d not found in key‑manager %q", id, name)
+    }
+
+    info := keyPairInfo(km, kp)
+    info["service_name"] = km.ServiceName
+    return &logical.Response{Data: info}, nil
+}
+
+// updateKey handles POST key‑managers/{name}/keys/{key_id}
+func (b *Backend) updateKey(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    id := data.Get("key_id").(int)
+
+    labels, setLabels, err := parseLabels(data, "labels")
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    metadata, setMetadata, err := parseMetadata(data, "metadata")
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil {
+        b.Logger().Error("Failed to retrieve key‑manager", "name", name, "error", err)
+        return nil, err
+    }
+    if km == nil {
+        return nil, fmt.Errorf("key‑manager %q not found", name)
+    }
+    kp := km.keyPairByID(id)
+    if kp == nil {
+        return nil, fmt.Errorf("key %d not found in key‑manager %q", id, name)
+    }
+    if setLabels {
+        kp.Labels = labels
+    }
+    if setMetadata {
+        kp.Metadata = metadata
+    }
+    if v, ok := data.GetOk("disabled"); ok {
+        kp.Disabled = v.(bool)
+    }
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return b.readKey(ctx, req, data)
+}
+
+// keyFilter holds the list filters of pathListKeys.
+type keyFilter struct {
+    walletVersion string
+    label         string
+    status        string
+    createdAfter  time.Time
+    createdBefore time.Time
+}
+
+func (f *keyFilter) match(km *KeyManager, kp *KeyPair) bool {
+    if f.walletVersion != "" && kp.WalletVersion != f.walletVersion {
+        return false
+    }
+    if f.label != "" && !containsString(kp.Labels, f.label) {
+        return false
+    }
+    if f.status != "" && km.keyStatus(kp) != f.status {
+        return false
+    }
+    if !f.createdAfter.IsZero() && kp.CreatedAt.Before(f.createdAfter) {
+        return false
+    }
+    if !f.createdBefore.IsZero() && !kp.CreatedAt.Before(f.createdBefore) {
+        return false
+    }
+    return true
+}
+
+// listKeys handles LIST key‑managers/{name}/keys
+func (b *Backend) listKeys(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+
+    after := 0
+    if raw := data.Get("after").(string); raw != "" {
+        id, err := strconv.Atoi(raw)
+        if err != nil {
+            return logical.ErrorResponse("after must be a key id"), nil
+        }
+        after = id
+    }
+    limit := data.Get("limit").(int)
+    if limit <= 0 {
+        return logical.ErrorResponse("limit must be positive"), nil
+    }
+
+    filter := &keyFilter{
+        walletVersion: data.Get("wallet_version").(string),
+        label:         data.Get("label").(string),
+        status:        data.Get("status").(string),
+    }
+    if v, ok := data.GetOk("created_after"); ok {
+        filter.createdAfter = v.(time.Time)
+    }
+    if v, ok := data.GetOk("created_before"); ok {
+        filter.createdBefore = v.(time.Time)
+    }
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil {
+        b.Logger().Error("Failed to retrieve key‑manager", "name", name, "error", err)
+        return nil, err
+    }
+    if km == nil {
+        return nil, fmt.Errorf("key‑manager %q not found", name)
+    }
+
+    keys := make([]string, 0, limit)
+    keyInfo := make(map[string]interface{}, limit)
+    for _, kp := range km.KeyPairs {
+        if kp.ID <= after || !filter.match(km, kp) {
+            continue
+        }
+        id := strconv.Itoa(kp.ID)
+        keys = append(keys, id)
+        keyInfo[id] = keyPairInfo(km, kp)
+        if len(keys) == limit {
+            break
+        }
+    }
+    return logical.ListResponseWithInfo(keys, keyInfo), nil
+}
+
+// keyPairInfo returns the public metadata of a key pair.
+func keyPairInfo(km *KeyManager, kp *KeyPair) map[string]interface{} {
+    info := map[string]interface{}{
+        "id":             kp.ID,
+        "address":        kp.Address,
+        "public_key":     kp.PublicKey,
+        "wallet_version": kp.WalletVersion,
+        "network":        kp.Network,
+        "workchain":      kp.Workchain,
+        "status":         km.keyStatus(kp),
+        "current":        kp.ID == km.CurrentVersion,
+        "rotated_from":   kp.RotatedFrom,
+        "exportable":     kp.Exportable,
+        "labels":         kp.Labels,
+        "metadata":       kp.Metadata,
+        "created_at":     "",
+    }
+    if !kp.CreatedAt.IsZero() {
+        info["created_at"] = kp.CreatedAt.Format(time.RFC3339)
+    }
+    return info
+}
@@ -0,0 +1,144 @@
+// internal/usecase/path_keys_test.go
+
+package usecase
+
+import (
+    "context"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestListKeysPagination(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    for i := 0; i < 5; i++ {
+        req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+        req.Storage = storage
+        req.Data = map[string]interface{}{"serviceName": "svc"}
+        _, err := b.HandleRequest(context.Background(), req)
+        require.NoError(t, err)
+    }
+
+    // First page
+    req := logical.TestRequest(t, logical.ListOperation, "key-managers/svc/keys")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"limit": 2}
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"1", "2"}, resp.Data["keys"])
+
+    info := resp.Data["key_info"].(map[string]interface{})["2"].(map[string]interface{})
+    assert.Equal(t, "v4R2", info["wallet_version"])
+    assert.Equal(t, keyStatusEnabled, info["status"])
+    assert.NotEmpty(t, info["address"])
+
+    // Next page
+    req = logical.TestRequest(t, logical.ListOperation, "key-managers/svc/keys")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"after": "2", "limit": 2}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"3", "4"}, resp.Data["keys"])
+
+    // Filters
+    req = logical.TestRequest(t, logical.ListOperation, "key-managers/svc/keys")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"status": keyStatusDisabled}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Empty(t, resp.Data["keys"])
+
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/svc/keys/3")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"disabled": true}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, keyStatusDisabled, resp.Data["status"])
+
+    req = logical.TestRequest(t, logical.ListOperation, "key-managers/svc/keys")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"status": keyStatusDisabled}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"3"}, resp.Data["keys"])
+
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/sign")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"hash": "00", "version": 3}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "disabled")
+
+    req = logical.TestRequest(t, logical.ListOperation, "key-managers/svc/keys")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"wallet_version": "v4R2", "created_after": "2000-01-01T00:00:00Z"}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Len(t, resp.Data["keys"], 5)
+}
+
+func TestKeyLabelsAndMetadata(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{
+        "serviceName":      "svc",
+        "labels":           "deposit,user-42",
+        "metadata":         map[string]interface{}{"user_id": "42"},
+        "service_labels":   "exchange",
+        "service_metadata": map[string]interface{}{"team": "payments"},
+    }
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"deposit", "user-42"}, resp.Data["labels"])
+
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "svc"}
+    _, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+
+    // Update the second key
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/svc/keys/2")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"labels": "hot", "metadata": map[string]interface{}{"purpose": "gas"}}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"hot"}, resp.Data["labels"])
+    assert.Equal(t, map[string]string{"purpose": "gas"}, resp.Data["metadata"])
+
+    // Search keys by label
+    req = logical.TestRequest(t, logical.ListOperation, "key-managers/svc/keys")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"label": "user-42"}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"1"}, resp.Data["keys"])
+
+    // Search key-managers by label
+    req = logical.TestRequest(t, logical.ListOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"label": "exchange"}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"svc"}, resp.Data["keys"])
+
+    // Update and read the key-manager
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/svc")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"labels": "exchange,treasury"}
+    _, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+
+    req = logical.TestRequest(t, logical.ReadOperation, "key-managers/svc")
+    req.Storage = storage
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"exchange", "treasury"}, resp.Data["labels"])
+    assert.Equal(t, map[string]string{"team": "payments"}, resp.Data["metadata"])
+}
@@ -0,0 +1,258 @@
+// internal/usecase/path_limits.go
+
+package usecase
+
+import (
+    "context"
+    "fmt"
+    "math/big"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+    unitBase  = "base"
+    unitHuman = "human"
+)
+
+func pathLimits(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/limits",
+        ExistenceCheck: b.pathExistenceCheck,
+        HelpSynopsis:   "Manage the spending limits of a key‑manager and its keys",
+        HelpDescription: `
+GET     — return the limits of the key‑manager and of each key with the amount spent in each window
+POST    — set the limit of one asset (ton or an allowlisted jetton master) for the key‑manager, or for
+          one key with key_id: max_per_tx and/or max_per_window over window, in base units, or in
+          human units with unit=human for TON and allowlisted jettons
+DELETE  — remove the limit of asset, or all limits of the scope without asset
+Limits are enforced on every txn path; key‑manager limits count the transfers of all its keys.
+        `,
+        Fields: map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+            "key_id": {
+                Type:        framework.TypeInt,
+                Description: "(Optional) Key version the limit applies to. Omit for a key‑manager limit.",
+            },
+            "asset": {
+                Type:        framework.TypeString,
+                Description: `"ton", or the address of a jetton master.`,
+                Default:     assetTON,
+            },
+            "max_per_tx": {
+                Type:        framework.TypeString,
+                Description: "(Optional) Maximum amount in one transaction, in nanotons or jetton base units.",
+            },
+            "max_per_window": {
+                Type:        framework.TypeString,
+                Description: "(Optional) Maximum total amount over the rolling window.",
+            },
+            "window": {
+                Type:        framework.TypeDurationSecond,
+                Description: "Length of the rolling window, e.g. 24h. Required with max_per_window.",
+            },
+            "unit": {
+                Type:          framework.TypeString,
+                Description:   "Unit of the amounts: base (nanotons, jetton base units) or human (e.g. 1.5 TON, using the decimals of allowlisted jettons).",
+                Default:       unitBase,
+                AllowedValues: []interface{}{unitBase, unitHuman},
+            },
+        },
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.ReadOperation:   &framework.PathOperation{Callback: b.readLimits},
+            logical.CreateOperation: &framework.PathOperation{Callback: b.writeLimit},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.writeLimit},
+            logical.DeleteOperation: &framework.PathOperation{Callback: b.deleteLimit},
+        },
+    }
+}
+
+// readLimits handles GET key-managers/{name}/limits
+func (b *Backend) readLimits(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    ledger, err := b.spendLedger(ctx, req.Storage, km.ServiceName)
+    if err != nil {
+        return nil, err
+    }
+
+    now := time.Now().UTC()
+    keys := make(map[string]interface{})
+    for _, kp := range km.KeyPairs {
+        if len(kp.Limits) == 0 {
+            continue
+        }
+        id := kp.ID
+        keys[fmt.Sprint(id)] = limitsInfo(km, kp.Limits, ledger, now, func(e spendEntry) bool { return e.KeyID == id })
+    }
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "service_name": km.ServiceName,
+            "limits":       limitsInfo(km, km.Limits, ledger, now, func(spendEntry) bool { return true }),
+            "key_limits":   keys,
+        },
+    }, nil
+}
+
+// limitsInfo reports limits in base units, adding human amounts for assets of known decimals.
+func limitsInfo(km *KeyManager, limits spendingLimits, ledger *spendLedger, now time.Time, keep func(spendEntry) bool) map[string]interface{} {
+    out := make(map[string]interface{}, len(limits))
+    for asset, limit := range limits {
+        info := map[string]interface{}{
+            "max_per_tx":     limit.MaxPerTx,
+            "max_per_window": limit.MaxPerWindow,
+            "window":         int64(limit.Window.Seconds()),
+        }
+        var spent *big.Int
+        if limit.Window > 0 {
+            spent = ledger.spent(asset, now.Add(-limit.Window), keep)
+            info["spent_in_window"] = spent.String()
+        }
+        if decimals, ok := km.assetDecimals(asset); ok {
+            info["decimals"] = decimals
+            human := map[string]interface{}{}
+            for field, v := range map[string]string{"max_per_tx": limit.MaxPerTx, "max_per_window": limit.MaxPerWindow} {
+                if amount, ok := new(big.Int).SetString(v, 10); ok {
+                    human[field] = formatUnits(amount, decimals)
+                }
+            }
+            if spent != nil {
+                human["spent_in_window"] = formatUnits(spent, decimals)
+            }
+            info["human"] = human
+        }
+        out[asset] = info
+    }
+    return out
+}
+
+// writeLimit handles POST key-managers/{name}/limits
+func (b *Backend) writeLimit(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    limits, err := limitsScope(km, data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    asset, err := normalizeAsset(data.Get("asset").(string))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    // transfers can only be attributed to a jetton whose wallets the plugin can compute
+    if asset != assetTON && km.Jettons[asset] == nil {
+        return logical.ErrorResponse("jetton master %s has no known wallet code, allowlist it before setting a limit", asset), nil
+    }
+
+    parse := parseAmount
+    if data.Get("unit").(string) == unitHuman {
+        decimals, ok := km.assetDecimals(asset)
+        if !ok {
+            return logical.ErrorResponse(fmt.Sprintf("decimals of %s are unknown, allowlist the jetton master or use unit=base", asset)), nil
+        }
+        parse = func(s, field string) (*big.Int, error) { return parseUnits(s, decimals, field) }
+    }
+
+    limit := &spendingLimit{Window: time.Duration(data.Get("window").(int)) * time.Second}
+    for field, dst := range map[string]*string{"max_per_tx": &limit.MaxPerTx, "max_per_window": &limit.MaxPerWindow} {
+        s := strings.TrimSpace(data.Get(field).(string))
+        if s == "" {
+            continue
+        }
+        v, err := parse(s, field)
+        if err != nil {
+            return logical.ErrorResponse(err.Error()), nil
+        }
+        *dst = v.String()
+    }
+    if limit.MaxPerTx == "" && limit.MaxPerWindow == "" {
+        return logical.ErrorResponse("max_per_tx or max_per_window is required"), nil
+    }
+    if limit.MaxPerWindow != "" && limit.Window <= 0 {
+        return logical.ErrorResponse("window is required with max_per_window"), nil
+    }
+    if limit.MaxPerWindow == "" {
+        limit.Window = 0
+    }
+    (*limits)[asset] = limit
+
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return b.readLimits(ctx, req, data)
+}
+
+// deleteLimit handles DELETE key-managers/{name}/limits
+func (b *Backend) deleteLimit(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    limits, err := limitsScope(km, data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    if raw, ok := data.GetOk("asset"); ok {
+        asset, err := normalizeAsset(raw.(string))
+        if err != nil {
+            return logical.ErrorResponse(err.Error()), nil
+        }
+        delete(*limits, asset)
+    } else {
+        *limits = nil
+    }
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return nil, nil
+}
+
+// limitsScope returns the limits a request addresses, allocating them if needed.
+func limitsScope(km *KeyManager, data *framework.FieldData) (*spendingLimits, error) {
+    limits := &km.Limits
+    if id, ok := data.GetOk("key_id"); ok {
+        kp := km.keyPairByID(id.(int))
+        if kp == nil {
+            return nil, fmt.Errorf("key %d not found in key-manager %q", id, km.ServiceName)
+        }
+        limits = &kp.Limits
+    }
+    if *limits == nil {
+        *limits = spendingLimits{}
+    }
+    return limits, nil
+}
+
+// normalizeAsset returns "ton" or the raw address of a jetton master.
+func normalizeAsset(asset string) (string, error) {
+    asset = strings.TrimSpace(asset)
+    if asset == "" || strings.EqualFold(asset, assetTON) {
+        return assetTON, nil
+    }
+    raw, err := normalizeAddress(asset)
+    if err != nil {
+        return "", fmt.Errorf("asset must be %q or a jetton master address: %w", assetTON, err)
+    }
+    return raw, nil
+}
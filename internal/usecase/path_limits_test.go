@@ -0,0 +1,100 @@
+// internal/usecase/path_limits_test.go
+
+package usecase
+
+import (
+    "context"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+const testJettonMaster = "0:2222222222222222222222222222222222222222222222222222222222222222"
+
+func TestSpendingLimits(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "svc"}
+    _, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+
+    setLimit := func(data map[string]interface{}) {
+        requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers/svc/limits", "", data))
+    }
+    // key-manager: 10 TON per tx; key 1: 15 TON per day
+    setLimit(map[string]interface{}{"max_per_tx": "10000000000"})
+    setLimit(map[string]interface{}{"key_id": 1, "max_per_window": "15000000000", "window": "24h"})
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/svc/limits")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"asset": testJettonMaster, "max_per_tx": "100"}
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.True(t, resp.IsError(), "jetton limits need the wallet code of the master")
+    assert.Contains(t, resp.Error().Error(), "no known wallet code")
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/txn/jetton/transfer")
+    req.Storage = storage
+    req.Data = map[string]interface{}{
+        "jettonWallet": testDestination, "jetton_master": testJettonMaster, "destination": testDestination, "amount": "1", "seqno": 1,
+    }
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "cannot be verified")
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/svc/jettons")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"master": testJettonMaster, "decimals": 6, "wallet_code": testWalletCode(t)}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    setLimit(map[string]interface{}{"asset": testJettonMaster, "max_per_tx": "100"})
+
+    sendTon := func(amount string) *logical.Response {
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/ton/transfer", "", map[string]interface{}{"destination": testDestination, "amount": amount, "seqno": 1})
+    }
+    resp = sendTon("11000000000")
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "spending limit exceeded")
+
+    // mode 128 sends the whole balance whatever the amount
+    resp = doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/ton/transfer", "", map[string]interface{}{"destination": testDestination, "amount": "0", "mode": 128, "seqno": 1})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "whole wallet balance")
+
+    assert.False(t, sendTon("8000000000").IsError())
+    assert.True(t, sendTon("8000000000").IsError(), "window total")
+    assert.False(t, sendTon("7000000000").IsError())
+
+    req = logical.TestRequest(t, logical.ReadOperation, "key-managers/svc/limits")
+    req.Storage = storage
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    keyLimits := resp.Data["key_limits"].(map[string]interface{})["1"].(map[string]interface{})
+    assert.Equal(t, "15000000000", keyLimits[assetTON].(map[string]interface{})["spent_in_window"])
+
+    // Jetton limits need the master and are checked per master
+    sendJetton := func(amount string, master string) *logical.Response {
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/jetton/transfer", "", map[string]interface{}{
+            "destination":   testDestination,
+            "amount":        amount,
+            "ton_amount":    "0",
+            "jetton_master": master,
+            "seqno":         1,
+        })
+    }
+    assert.True(t, sendJetton("50", "").IsError())
+    assert.True(t, sendJetton("50", testDestination).IsError(), "unverifiable master")
+    assert.True(t, sendJetton("101", testJettonMaster).IsError())
+    assert.False(t, sendJetton("100", testJettonMaster).IsError())
+
+    // Removing the limits lifts them
+    req = logical.TestRequest(t, logical.DeleteOperation, "key-managers/svc/limits")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"key_id": 1}
+    _, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.False(t, sendTon("9000000000").IsError())
+}
@@ -0,0 +1,115 @@
+// internal/usecase/path_lookup.go
+
+package usecase
+
+import (
+    "context"
+    "encoding/hex"
+    "fmt"
+    "strings"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+// pathLookup defines the reverse lookups from an address or a public key to its key‑manager.
+func pathLookup(b *Backend) []*framework.Path {
+    return []*framework.Path{
+        {
+            Pattern:         "addresses/" + framework.MatchAllRegex("address"),
+            HelpSynopsis:    "Find the key‑manager and key owning a TON address",
+            HelpDescription: "GET with any friendly (bounceable or not, mainnet or testnet) or raw address form.",
+            Fields: map[string]*framework.FieldSchema{
+                "address": {Type: framework.TypeString, Description: "TON address in friendly or raw form."},
+            },
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.ReadOperation: &framework.PathOperation{Callback: b.lookupAddress},
+            },
+        },
+        {
+            Pattern:         "public-keys/" + framework.GenericNameRegex("public_key"),
+            HelpSynopsis:    "Find the key‑manager and key owning an Ed25519 public key",
+            HelpDescription: "GET with the hex-encoded 32-byte public key.",
+            Fields: map[string]*framework.FieldSchema{
+                "public_key": {Type: framework.TypeString, Description: "Hex-encoded Ed25519 public key."},
+            },
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.ReadOperation: &framework.PathOperation{Callback: b.lookupPublicKey},
+            },
+        },
+    }
+}
+
+// lookupAddress handles GET addresses/{address}
+func (b *Backend) lookupAddress(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    raw, err := normalizeAddress(data.Get("address").(string))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    return b.lookupResponse(ctx, req, addressIndexPrefix+raw)
+}
+
+// lookupPublicKey handles GET public-keys/{public_key}
+func (b *Backend) lookupPublicKey(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    pub := strings.ToLower(strings.TrimPrefix(data.Get("public_key").(string), "0x"))
+    if raw, err := hex.DecodeString(pub); err != nil || len(raw) != 32 {
+        return logical.ErrorResponse("public_key must be 32-byte hex"), nil
+    }
+    return b.lookupResponse(ctx, req, publicKeyIndexPrefix+pub)
+}
+
+func (b *Backend) lookupResponse(ctx context.Context, req *logical.Request, p string) (*logical.Response, error) {
+    refs, err := lookupKeyRefs(ctx, req.Storage, p)
+    if err != nil {
+        b.Logger().Error("Failed to read index entry", "path", p, "error", err)
+        return nil, err
+    }
+    if len(refs) == 0 {
+        return nil, nil
+    }
+
+    // the oldest owner answers the lookup; owners lists every key-manager holding the key
+    owners := make([]map[string]interface{}, 0, len(refs))
+    var km *KeyManager
+    var kp *KeyPair
+    for _, ref := range refs {
+        owner, err := b.retrieveKeyManager(ctx, req, ref.ServiceName)
+        if err != nil {
+            return nil, err
+        }
+        var key *KeyPair
+        if owner != nil {
+            key = owner.keyPairByID(ref.KeyID)
+        }
+        if key == nil {
+            return nil, fmt.Errorf("index entry %q points to missing key %d of %q", p, ref.KeyID, ref.ServiceName)
+        }
+        if kp == nil {
+            km, kp = owner, key
+        }
+        owners = append(owners, map[string]interface{}{"service_name": owner.ServiceName, "key_id": key.ID})
+    }
+
+    raw, err := normalizeAddress(kp.Address)
+    if err != nil {
+        return nil, err
+    }
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "service_name": km.ServiceName,
+            "key_id":       kp.ID,
+            "address":      kp.Address,
+            "raw_address":  raw,
+            "public_key":   kp.PublicKey,
+            "owners":       owners,
+        },
+    }, nil
+}
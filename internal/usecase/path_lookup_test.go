@@ -0,0 +1,109 @@
+// internal/usecase/path_lookup_test.go
+
+package usecase
+
+import (
+    "context"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/tonkeeper/tongo/ton"
+)
+
+func TestLookupAddressAndPublicKey(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "svc"}
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    addr := resp.Data["address"].(string)
+    pub := resp.Data["public_key"].(string)
+
+    id := ton.MustParseAccountID(addr)
+    for _, form := range []string{addr, id.ToRaw(), id.ToHuman(false, false), id.ToHuman(true, true)} {
+        req = logical.TestRequest(t, logical.ReadOperation, "addresses/"+form)
+        req.Storage = storage
+        resp, err = b.HandleRequest(context.Background(), req)
+        require.NoError(t, err, form)
+        require.NotNil(t, resp, form)
+        assert.Equal(t, "svc", resp.Data["service_name"])
+        assert.Equal(t, 1, resp.Data["key_id"])
+    }
+
+    req = logical.TestRequest(t, logical.ReadOperation, "public-keys/"+pub)
+    req.Storage = storage
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, addr, resp.Data["address"])
+
+    // Deleting the key-manager drops the index entries
+    req = logical.TestRequest(t, logical.DeleteOperation, "key-managers/svc")
+    req.Storage = storage
+    _, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+
+    req = logical.TestRequest(t, logical.ReadOperation, "addresses/"+addr)
+    req.Storage = storage
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Nil(t, resp)
+}
+
+func TestInitializeBuildsIndex(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    km := &KeyManager{ServiceName: "legacy"}
+    km.addKeyPair(&KeyPair{
+        PrivateKey: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+        PublicKey:  "aa",
+        Address:    "0:0000000000000000000000000000000000000000000000000000000000000001",
+    })
+    req := &logical.Request{Storage: storage}
+    require.NoError(t, b.storeKeyManager(context.Background(), req, km))
+
+    require.NoError(t, b.Initialize(context.Background(), &logical.InitializationRequest{Storage: storage}))
+
+    req = logical.TestRequest(t, logical.ReadOperation, "addresses/0:01")
+    req.Storage = storage
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.NotNil(t, resp)
+    assert.Equal(t, "legacy", resp.Data["service_name"])
+}
+
+func TestLookupSharedKey(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    // the same seed imported into two key-managers
+    var addr string
+    for _, svc := range []string{"first", "second"} {
+        resp := requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers", "", map[string]interface{}{
+            "serviceName": svc, "privateKey": testSeed,
+        }))
+        addr = resp.Data["address"].(string)
+    }
+    resp := doRequest(t, b, storage, logical.ReadOperation, "addresses/"+addr, "", nil)
+    require.NotNil(t, resp)
+    assert.Equal(t, "first", resp.Data["service_name"])
+    assert.Equal(t, []map[string]interface{}{
+        {"service_name": "first", "key_id": 1},
+        {"service_name": "second", "key_id": 1},
+    }, resp.Data["owners"])
+
+    // deleting one owner keeps the lookup of the other
+    doRequest(t, b, storage, logical.DeleteOperation, "key-managers/first", "", nil)
+    resp = doRequest(t, b, storage, logical.ReadOperation, "addresses/"+addr, "", nil)
+    require.NotNil(t, resp)
+    assert.Equal(t, "second", resp.Data["service_name"])
+    pub := resp.Data["public_key"].(string)
+    resp = doRequest(t, b, storage, logical.ReadOperation, "public-keys/"+pub, "", nil)
+    require.NotNil(t, resp)
+    assert.Equal(t, "second", resp.Data["service_name"])
+
+    doRequest(t, b, storage, logical.DeleteOperation, "key-managers/second", "", nil)
+    assert.Nil(t, doRequest(t, b, storage, logical.ReadOperation, "addresses/"+addr, "", nil))
+}
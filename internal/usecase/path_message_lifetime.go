@@ -0,0 +1,126 @@
+// internal/usecase/path_message_lifetime.go
+
+package usecase
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+func pathMessageLifetime(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/message-lifetime",
+        ExistenceCheck: b.pathExistenceCheck,
+        HelpSynopsis:   "Manage the lifetime of the messages a key‑manager signs",
+        HelpDescription: `
+GET     — return the overrides and the effective bounds
+POST    — override the mount default_message_lifetime and max_message_lifetime; a key‑manager
+          may only tighten the mount maximum
+DELETE  — remove the overrides
+Txn requests without valid_until expire after the default lifetime; requests with a valid_until
+further away than the maximum are rejected. Applies to every wallet version.
+        `,
+        Fields: map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+            "default_lifetime": {
+                Type:        framework.TypeDurationSecond,
+                Description: "Lifetime of signed messages without valid_until. 0 uses the mount default.",
+            },
+            "max_lifetime": {
+                Type:        framework.TypeDurationSecond,
+                Description: "Longest lifetime a valid_until may ask for. 0 uses the mount maximum.",
+            },
+        },
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.ReadOperation:   &framework.PathOperation{Callback: b.readMessageLifetime},
+            logical.CreateOperation: &framework.PathOperation{Callback: b.writeMessageLifetime},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.writeMessageLifetime},
+            logical.DeleteOperation: &framework.PathOperation{Callback: b.deleteMessageLifetime},
+        },
+    }
+}
+
+// readMessageLifetime handles GET key-managers/{name}/message-lifetime
+func (b *Backend) readMessageLifetime(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    cfg, err := b.config(ctx, req.Storage)
+    if err != nil {
+        return nil, err
+    }
+    var own messageLifetime
+    if km.MessageLifetime != nil {
+        own = *km.MessageLifetime
+    }
+    eff := cfg.messageLifetime(km)
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "service_name":               km.ServiceName,
+            "default_lifetime":           int64(own.Default.Seconds()),
+            "max_lifetime":               int64(own.Max.Seconds()),
+            "effective_default_lifetime": int64(eff.Default.Seconds()),
+            "effective_max_lifetime":     int64(eff.Max.Seconds()),
+        },
+    }, nil
+}
+
+// writeMessageLifetime handles POST key-managers/{name}/message-lifetime
+func (b *Backend) writeMessageLifetime(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    l := &messageLifetime{
+        Default: time.Duration(data.Get("default_lifetime").(int)) * time.Second,
+        Max:     time.Duration(data.Get("max_lifetime").(int)) * time.Second,
+    }
+    if l.Default < 0 || l.Max < 0 {
+        return logical.ErrorResponse("lifetimes must not be negative"), nil
+    }
+    if l.Default == 0 && l.Max == 0 {
+        return logical.ErrorResponse("default_lifetime or max_lifetime is required"), nil
+    }
+    if l.Max > 0 && l.Default > l.Max {
+        return logical.ErrorResponse("default_lifetime must not exceed max_lifetime"), nil
+    }
+
+    km.MessageLifetime = l
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return b.readMessageLifetime(ctx, req, data)
+}
+
+// deleteMessageLifetime handles DELETE key-managers/{name}/message-lifetime
+func (b *Backend) deleteMessageLifetime(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    km.MessageLifetime = nil
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return nil, nil
+}
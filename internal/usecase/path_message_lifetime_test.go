@@ -0,0 +1,98 @@
+// internal/usecase/path_message_lifetime_test.go
+
+package usecase
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestMessageLifetime(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "svc"}
+    _, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+
+    transfer := func(validUntil int64) *logical.Response {
+        data := map[string]interface{}{"destination": testDestination, "amount": "1000", "seqno": 1}
+        if validUntil != 0 {
+            data["valid_until"] = validUntil
+        }
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/ton/transfer", "", data)
+    }
+
+    // the mount default fills in valid_until
+    now := time.Now()
+    resp := transfer(0)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    vu := resp.Data["valid_until"].(int64)
+    assert.InDelta(t, now.Add(3*time.Minute).Unix(), vu, 2)
+    assert.Equal(t, time.Unix(vu, 0).UTC().Format(time.RFC3339), resp.Data["expires_at"])
+
+    // no maximum by default
+    resp = transfer(now.Add(48 * time.Hour).Unix())
+    require.False(t, resp.IsError(), "%v", resp.Data)
+
+    resp = transfer(now.Add(-time.Minute).Unix())
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "in the past")
+
+    req = logical.TestRequest(t, logical.UpdateOperation, "config")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"default_message_lifetime": "2m", "max_message_lifetime": "1h"}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+
+    resp = transfer(now.Add(2 * time.Hour).Unix())
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "maximum message lifetime")
+    resp = transfer(now.Add(30 * time.Minute).Unix())
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, now.Add(30*time.Minute).Unix(), resp.Data["valid_until"])
+
+    // a key-manager may only tighten the mount maximum
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/svc/message-lifetime")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"default_lifetime": 30, "max_lifetime": "10m"}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.EqualValues(t, 30, resp.Data["effective_default_lifetime"])
+    assert.EqualValues(t, 600, resp.Data["effective_max_lifetime"])
+
+    resp = transfer(now.Add(30 * time.Minute).Unix())
+    require.True(t, resp.IsError())
+    now = time.Now()
+    resp = transfer(0)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.InDelta(t, now.Add(30*time.Second).Unix(), resp.Data["valid_until"], 2)
+
+    req.Data = map[string]interface{}{"max_lifetime": "2h"}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.EqualValues(t, 3600, resp.Data["effective_max_lifetime"])
+    assert.EqualValues(t, 120, resp.Data["effective_default_lifetime"])
+
+    req = logical.TestRequest(t, logical.DeleteOperation, "key-managers/svc/message-lifetime")
+    req.Storage = storage
+    _, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    resp = transfer(time.Now().Add(30 * time.Minute).Unix())
+    require.False(t, resp.IsError(), "%v", resp.Data)
+
+    req = logical.TestRequest(t, logical.UpdateOperation, "config")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"default_message_lifetime": "2h"}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.True(t, resp.IsError())
+}
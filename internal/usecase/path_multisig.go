@@ -0,0 +1,339 @@
+// internal/usecase/path_multisig.go
+package usecase
+
+import (
+    "context"
+    "fmt"
+    "math/big"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+func pathMultisig(b *Backend) []*framework.Path {
+    return []*framework.Path{
+        {
+            Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/txn/multisig/new-order",
+            ExistenceCheck: b.pathExistenceCheck,
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.CreateOperation: &framework.PathOperation{Callback: b.signMultisigNewOrder},
+            },
+            HelpSynopsis: "Sign a multisig v2 new_order from the key‑manager wallet",
+            HelpDescription: `
+POST multisig, actions, signer_index → a wallet message sending new_order to the multisig, as the other
+txn paths, with the order decoded from the signed body. Actions are transfers ({destination, amount,
+comment or body, bounce, mode}) and update_params ({threshold, signers, proposers}). With signer=true
+(default) the wallet is signers[signer_index] of the multisig and its proposal counts as an approval.
+            `,
+            Fields: txnFields(map[string]*framework.FieldSchema{
+                "multisig": {
+                    Type:        framework.TypeString,
+                    Description: "Multisig contract address.",
+                },
+                "actions": {
+                    Type:        framework.TypeSlice,
+                    Description: "Order actions, each an object with a type and its fields.",
+                },
+                "signer": {
+                    Type:        framework.TypeBool,
+                    Description: "(Optional) Propose as a signer rather than a proposer.",
+                    Default:     true,
+                },
+                "signer_index": {
+                    Type:        framework.TypeInt,
+                    Description: "Index of the wallet among the multisig signers, or proposers with signer=false.",
+                },
+                "order_seqno": {
+                    Type:        framework.TypeString,
+                    Description: "(Optional) Seqno of the new order. Defaults to the next seqno of the multisig.",
+                },
+                "expiration_date": {
+                    Type:        framework.TypeInt,
+                    Description: "(Optional) Unix time after which the order can no longer be approved. Defaults to 24h from now.",
+                },
+                "amount": {
+                    Type:        framework.TypeString,
+                    Description: "(Optional) Nanotons sent to the multisig to deploy the order.",
+                    Default:     defaultNewOrderAmount,
+                },
+                "query_id": {
+                    Type:        framework.TypeInt64,
+                    Description: "(Optional) Query id of the message.",
+                },
+            }),
+        },
+        {
+            Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/txn/multisig/approve",
+            ExistenceCheck: b.pathExistenceCheck,
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.CreateOperation: &framework.PathOperation{Callback: b.signMultisigApprove},
+            },
+            HelpSynopsis: "Sign a multisig v2 approval of an order from the key‑manager wallet",
+            HelpDescription: `
+POST multisig, order_seqno, signer_index → a wallet message sending approve to the order contract,
+which is where multisig v2 counts approvals. The order address is computed from the multisig and
+order_seqno; an order_address that differs is refused. The order cell cannot be checked against the
+order contract, so its actions are decoded as unverified_actions, for display only.
+            `,
+            Fields: txnFields(map[string]*framework.FieldSchema{
+                "multisig": {
+                    Type:        framework.TypeString,
+                    Description: "Multisig contract address.",
+                },
+                "order_address": {
+                    Type:        framework.TypeString,
+                    Description: "(Optional) Address of the order contract, checked against the computed one.",
+                },
+                "order_seqno": {
+                    Type:        framework.TypeString,
+                    Description: "Seqno of the order.",
+                },
+                "signer_index": {
+                    Type:        framework.TypeInt,
+                    Description: "Index of the wallet among the multisig signers.",
+                },
+                "order": {
+                    Type:        framework.TypeString,
+                    Description: "(Optional) Base64 BOC of the order actions, decoded unverified in the response.",
+                },
+                "amount": {
+                    Type:        framework.TypeString,
+                    Description: "(Optional) Nanotons sent to the order for fees.",
+                    Default:     defaultApproveAmount,
+                },
+                "query_id": {
+                    Type:        framework.TypeInt64,
+                    Description: "(Optional) Query id of the message.",
+                },
+            }),
+        },
+    }
+}
+
+// signMultisigNewOrder handles POST key-managers/{name}/txn/multisig/new-order
+func (b *Backend) signMultisigNewOrder(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    params, err := parseTxnParams(data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    multisig, _, err := parseDestination(data, "multisig", "")
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    order, err := parseMultisigOrder(data.Get("actions").([]interface{}))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    index, err := signerIndex(data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    seqno, err := orderSeqno(data.Get("order_seqno").(string), maxOrderSeqno)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    expiration := time.Now().Add(24 * time.Hour).Unix()
+    if v, ok := data.GetOk("expiration_date"); ok {
+        if expiration = int64(v.(int)); expiration <= time.Now().Unix() || expiration >= 1<<48 {
+            return logical.ErrorResponse("expiration_date must be a future unix time"), nil
+        }
+    }
+    amount, err := parseNanotons(data.Get("amount").(string), "amount")
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    c := boc.NewCell()
+    if err := tlb.Marshal(c, multisigNewOrder{
+        QueryID:        uint64(data.Get("query_id").(int64)),
+        OrderSeqno:     tlb.Uint256(*seqno),
+        Signer:         data.Get("signer").(bool),
+        Index:          index,
+        ExpirationDate: tlb.Uint48(expiration),
+        Order:          order,
+    }); err != nil {
+        return nil, fmt.Errorf("failed to build new_order: %w", err)
+    }
+    // the response shows the order as decoded back from the signed body
+    c.ResetCounters()
+    var signed multisigNewOrder
+    if err := tlb.Unmarshal(c, &signed); err != nil {
+        return nil, fmt.Errorf("failed to decode new_order: %w", err)
+    }
+    actions, err := orderPreview(signed.Order)
+    if err != nil {
+        return nil, err
+    }
+    c.ResetCounters()
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    kp, err := km.signingKey(data.Get("version").(int))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    // the multisig sends what the order transfers: it is held to the destination lists
+    for i, a := range actions {
+        if a["type"] != multisigActionTransfer {
+            continue
+        }
+        addr, err := ton.ParseAccountID(a["destination"].(string))
+        if err != nil {
+            return nil, err
+        }
+        if err := km.checkDestination(addr); err != nil {
+            return logical.ErrorResponse("actions[%d]: %s", i, err), nil
+        }
+    }
+    orderSeqnoOut := "next"
+    if seqno.Cmp(maxOrderSeqno) != 0 {
+        orderSeqnoOut = seqno.String()
+    }
+    params.details = map[string]interface{}{
+        "order": map[string]interface{}{
+            "multisig":        multisig.ToRaw(),
+            "order_seqno":     orderSeqnoOut,
+            "signer":          signed.Signer,
+            "signer_index":    int(signed.Index),
+            "expiration_date": expiration,
+            "actions":         actions,
+        },
+    }
+    params.actions = actions
+    msg := &outMessage{
+        Kind:        txnKindMultisig,
+        Destination: multisig,
+        Amount:      amount,
+        Op:          opMultisigNewOrder,
+        message: wallet.Message{
+            Amount:  tlb.Grams(amount),
+            Address: multisig,
+            Body:    c,
+            Bounce:  true,
+            Mode:    wallet.DefaultMessageMode,
+        },
+    }
+    return b.signTransfers(ctx, req, km, kp, params, []*outMessage{msg})
+}
+
+// signMultisigApprove handles POST key-managers/{name}/txn/multisig/approve
+func (b *Backend) signMultisigApprove(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    params, err := parseTxnParams(data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    multisig, _, err := parseDestination(data, "multisig", "")
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    if strings.TrimSpace(data.Get("order_seqno").(string)) == "" {
+        return logical.ErrorResponse("order_seqno is required"), nil
+    }
+    seqno, err := orderSeqno(data.Get("order_seqno").(string), nil)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    orderAddr, err := multisigOrderAddress(multisig, seqno)
+    if err != nil {
+        return nil, fmt.Errorf("failed to compute the order address: %w", err)
+    }
+    if strings.TrimSpace(data.Get("order_address").(string)) != "" {
+        claimed, _, err := parseDestination(data, "order_address", "")
+        if err != nil {
+            return logical.ErrorResponse(err.Error()), nil
+        }
+        if claimed != orderAddr {
+            return logical.ErrorResponse("order_address %s is not order %s of multisig %s, which is %s",
+                claimed.ToRaw(), seqno, multisig.ToRaw(), orderAddr.ToRaw()), nil
+        }
+    }
+    index, err := signerIndex(data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    amount, err := parseNanotons(data.Get("amount").(string), "amount")
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    order := map[string]interface{}{
+        "multisig":      multisig.ToRaw(),
+        "order_address": orderAddr.ToRaw(),
+        "order_seqno":   seqno.String(),
+        "signer_index":  int(index),
+    }
+    if s := strings.TrimSpace(data.Get("order").(string)); s != "" {
+        cell, err := singleCell(s, "order")
+        if err != nil {
+            return logical.ErrorResponse(err.Error()), nil
+        }
+        actions, err := decodeMultisigOrder(cell)
+        if err != nil {
+            return logical.ErrorResponse(err.Error()), nil
+        }
+        // the order contract keeps its actions out of its address: nothing ties them to the order
+        order["unverified_actions"] = actions
+    }
+
+    c := boc.NewCell()
+    if err := tlb.Marshal(c, multisigApprove{QueryID: uint64(data.Get("query_id").(int64)), SignerIndex: index}); err != nil {
+        return nil, fmt.Errorf("failed to build approve: %w", err)
+    }
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    kp, err := km.signingKey(data.Get("version").(int))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    params.details = map[string]interface{}{"order": order}
+    msg := &outMessage{
+        Kind:        txnKindMultisig,
+        Destination: orderAddr,
+        Amount:      amount,
+        Op:          opMultisigApprove,
+        message: wallet.Message{
+            Amount:  tlb.Grams(amount),
+            Address: orderAddr,
+            Body:    c,
+            Bounce:  true,
+            Mode:    wallet.DefaultMessageMode,
+        },
+    }
+    return b.signTransfers(ctx, req, km, kp, params, []*outMessage{msg})
+}
+
+func signerIndex(data *framework.FieldData) (uint8, error) {
+    v, ok := data.GetOk("signer_index")
+    if !ok {
+        return 0, fmt.Errorf("signer_index is required")
+    }
+    if v.(int) < 0 || v.(int) > 254 {
+        return 0, fmt.Errorf("signer_index must be between 0 and 254, got %d", v.(int))
+    }
+    return uint8(v.(int)), nil
+}
+
+// orderSeqno parses a 256-bit order seqno, returning def when s is empty.
+func orderSeqno(s string, def *big.Int) (*big.Int, error) {
+    s = strings.TrimSpace(s)
+    if s == "" {
+        return def, nil
+    }
+    v, ok := new(big.Int).SetString(s, 10)
+    if !ok || v.Sign() < 0 || v.Cmp(maxOrderSeqno) > 0 {
+        return nil, fmt.Errorf("order_seqno must be an unsigned 256-bit integer, got %q", s)
+    }
+    return v, nil
+}
@@ -0,0 +1,198 @@
+// internal/usecase/path_multisig_test.go
+
+package usecase
+
+import (
+    "context"
+    "math/big"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+)
+
+func TestMultisig(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "ms", "wallet_version": "v4R2"}
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+
+    multisig := func(op string, data map[string]interface{}) *logical.Response {
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/ms/txn/multisig/"+op, "", data)
+    }
+    // message returns the internal message carried by the signed v4 wallet body
+    message := func(resp *logical.Response) tlb.Message {
+        cells, err := boc.DeserializeBocBase64(resp.Data["signed_boc"].(string))
+        require.NoError(t, err)
+        var ext tlb.Message
+        require.NoError(t, tlb.Unmarshal(cells[0], &ext))
+        body := boc.Cell(ext.Body.Value)
+        require.Equal(t, 1, body.RefsSize())
+        var msg tlb.Message
+        require.NoError(t, tlb.Unmarshal(body.Refs()[0], &msg))
+        return msg
+    }
+
+    ms, err := ton.ParseAccountID(testDestination)
+    require.NoError(t, err)
+    signer := "0:2222222222222222222222222222222222222222222222222222222222222222"
+    actions := []interface{}{
+        map[string]interface{}{"destination": testDestination, "amount": "1000", "comment": "salary"},
+        map[string]interface{}{"type": "update_params", "threshold": 2, "signers": []string{testDestination, signer}},
+    }
+    resp = multisig("new-order", map[string]interface{}{
+        "multisig": testDestination, "actions": actions, "signer_index": 1, "order_seqno": "5",
+        "expiration_date": 4000000000, "query_id": 9, "seqno": 1,
+    })
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    order := resp.Data["order"].(map[string]interface{})
+    assert.Equal(t, ms.ToRaw(), order["multisig"])
+    assert.Equal(t, "5", order["order_seqno"])
+    assert.Equal(t, 1, order["signer_index"])
+    assert.Equal(t, true, order["signer"])
+    assert.Equal(t, []map[string]interface{}{
+        {"index": 0, "type": "transfer", "destination": ms.ToRaw(), "amount": "1000", "bounce": true, "mode": 3,
+            "comment": "salary", "body_hash": order["actions"].([]map[string]interface{})[0]["body_hash"]},
+        {"index": 1, "type": "update_params", "threshold": 2, "signers": []string{ms.ToRaw(), signer}, "proposers": []string{}},
+    }, order["actions"])
+
+    msg := message(resp)
+    dest, err := ton.AccountIDFromTlb(msg.Info.IntMsgInfo.Dest)
+    require.NoError(t, err)
+    assert.Equal(t, ms, *dest)
+    assert.EqualValues(t, 200000000, msg.Info.IntMsgInfo.Value.Grams)
+    body := boc.Cell(msg.Body.Value)
+    var newOrder multisigNewOrder
+    require.NoError(t, tlb.Unmarshal(&body, &newOrder))
+    assert.EqualValues(t, 9, newOrder.QueryID)
+    assert.EqualValues(t, 1, newOrder.Index)
+    assert.True(t, newOrder.Signer)
+    assert.EqualValues(t, 4000000000, newOrder.ExpirationDate)
+    seqno := big.Int(newOrder.OrderSeqno)
+    assert.Equal(t, "5", seqno.String())
+    assert.Len(t, newOrder.Order.Keys(), 2)
+
+    // the next order seqno is the default
+    resp = multisig("new-order", map[string]interface{}{"multisig": testDestination, "actions": actions[:1], "signer_index": 0, "seqno": 2})
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, "next", resp.Data["order"].(map[string]interface{})["order_seqno"])
+
+    for _, tc := range []struct {
+        data map[string]interface{}
+        err  string
+    }{
+        {map[string]interface{}{"multisig": testDestination, "signer_index": 0}, "actions is required"},
+        {map[string]interface{}{"multisig": testDestination, "actions": actions}, "signer_index is required"},
+        {map[string]interface{}{"actions": actions, "signer_index": 0}, "multisig is required"},
+        {map[string]interface{}{"multisig": testDestination, "actions": actions, "signer_index": 0, "expiration_date": 1}, "expiration_date"},
+        {map[string]interface{}{"multisig": testDestination, "signer_index": 0, "actions": []interface{}{
+            map[string]interface{}{"type": "update_params", "threshold": 3, "signers": []string{signer}},
+        }}, "threshold"},
+    } {
+        resp = multisig("new-order", tc.data)
+        require.True(t, resp.IsError(), "%v", tc.data)
+        assert.Contains(t, resp.Error().Error(), tc.err)
+    }
+
+    // approvals go to the order computed from the multisig and order seqno; the
+    // order cell cannot be checked, so its actions are only shown as unverified
+    orderAddr, err := multisigOrderAddress(ms, big.NewInt(5))
+    require.NoError(t, err)
+    other, err := multisigOrderAddress(ms, big.NewInt(6))
+    require.NoError(t, err)
+    assert.NotEqual(t, orderAddr, other)
+    orderCell := boc.NewCell()
+    require.NoError(t, tlb.Marshal(orderCell, newOrder.Order))
+    orderBoc, err := orderCell.ToBocBase64()
+    require.NoError(t, err)
+    resp = multisig("approve", map[string]interface{}{
+        "multisig": testDestination, "order_seqno": "5", "signer_index": 0, "order": orderBoc, "seqno": 3,
+    })
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    approval := resp.Data["order"].(map[string]interface{})
+    assert.Equal(t, "5", approval["order_seqno"])
+    assert.Equal(t, orderAddr.ToRaw(), approval["order_address"])
+    assert.Equal(t, order["actions"], approval["unverified_actions"])
+    assert.Nil(t, approval["actions"])
+
+    msg = message(resp)
+    dest, err = ton.AccountIDFromTlb(msg.Info.IntMsgInfo.Dest)
+    require.NoError(t, err)
+    assert.Equal(t, orderAddr, *dest)
+    body = boc.Cell(msg.Body.Value)
+    var approve multisigApprove
+    require.NoError(t, tlb.Unmarshal(&body, &approve))
+    assert.EqualValues(t, 0, approve.SignerIndex)
+
+    for _, tc := range []struct {
+        data map[string]interface{}
+        err  string
+    }{
+        {map[string]interface{}{"multisig": testDestination, "signer_index": 0}, "order_seqno is required"},
+        {map[string]interface{}{"order_seqno": "5", "signer_index": 0}, "multisig is required"},
+        {map[string]interface{}{"multisig": testDestination, "order_seqno": "5", "order_address": other.ToRaw(), "signer_index": 0}, "is not order 5"},
+    } {
+        resp = multisig("approve", tc.data)
+        require.True(t, resp.IsError(), "%v", tc.data)
+        assert.Contains(t, resp.Error().Error(), tc.err)
+    }
+    resp = multisig("approve", map[string]interface{}{
+        "multisig": testDestination, "order_seqno": "5", "order_address": orderAddr.ToRaw(), "signer_index": 0, "seqno": 4,
+    })
+    require.False(t, resp.IsError(), "%v", resp.Data)
+
+    // orders are destinations like any other
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/ms/destinations")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"allowed_destinations": []string{testDestination}}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    resp = multisig("approve", map[string]interface{}{"multisig": testDestination, "order_seqno": "5", "signer_index": 0, "seqno": 4})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "not allowed")
+}
+
+func TestMultisigOrderPolicy(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers", "", map[string]interface{}{"serviceName": "ms", "wallet_version": "v4R2"}))
+    denied := "0:3333333333333333333333333333333333333333333333333333333333333333"
+    signer := "0:2222222222222222222222222222222222222222222222222222222222222222"
+    newOrder := func(actions ...interface{}) *logical.Response {
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/ms/txn/multisig/new-order", "", map[string]interface{}{
+            "multisig": testDestination, "actions": actions, "signer_index": 0, "seqno": 1,
+        })
+    }
+    transfer := map[string]interface{}{"destination": testDestination, "amount": "1000"}
+    updateParams := map[string]interface{}{"type": "update_params", "threshold": 1, "signers": []string{signer}}
+
+    // the transfers of the order are held to the destination lists
+    requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers/ms/destinations", "", map[string]interface{}{
+        "denied_destinations": []string{denied},
+    }))
+    resp := newOrder(transfer, map[string]interface{}{"destination": denied, "amount": "1000"})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "actions[1]")
+    requireOK(t, newOrder(transfer))
+
+    // policies see the actions of the order
+    requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers/ms/policy", "", map[string]interface{}{
+        "rules": []interface{}{map[string]interface{}{
+            "name": "no-signer-changes", "effect": "deny", "expression": `actions.exists(a, a.type == "update_params")`,
+        }},
+        "default_effect": "allow",
+    }))
+    resp = newOrder(transfer, updateParams)
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "denied by signing policy")
+    requireOK(t, newOrder(transfer))
+}
@@ -0,0 +1,280 @@
+// internal/usecase/path_policy.go
+
+package usecase
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+// policyFields returns the schema of a signing policy.
+func policyFields() map[string]*framework.FieldSchema {
+    return map[string]*framework.FieldSchema{
+        "rules": {
+            Type:        framework.TypeSlice,
+            Description: `Ordered rules, each {"name", "effect": "allow"|"deny", "expression": <CEL bool expression>}.`,
+        },
+        "default_effect": {
+            Type:          framework.TypeString,
+            Description:   "Effect when no rule matches.",
+            Default:       policyDeny,
+            AllowedValues: []interface{}{policyAllow, policyDeny},
+        },
+        "lists": {
+            Type:        framework.TypeMap,
+            Description: "(Optional) Named address lists available to rules as lists.<name>, e.g. a KYC list.",
+        },
+    }
+}
+
+func pathPolicy(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/policy",
+        ExistenceCheck: b.pathExistenceCheck,
+        HelpSynopsis:   "Manage the signing policy of a key‑manager",
+        HelpDescription: `
+GET     — return the policy
+POST    — replace the policy: ordered CEL rules, the default effect and named address lists
+DELETE  — remove the policy
+Rules are evaluated in order against every transaction and the first matching rule allows or
+denies it. Rules see messages (a list of {kind, op, destination, amount, comment, jetton_amount,
+jetton_master, recipient, mode, bounce, response_destination}), actions (what a transaction does besides
+sending messages, such as W5 extension actions), now (timestamp), entity_id, service_name,
+key_version and lists.
+Addresses are in raw form and amounts in base units.
+        `,
+        Fields: mergeFields(map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+        }, policyFields()),
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.ReadOperation:   &framework.PathOperation{Callback: b.readPolicy},
+            logical.CreateOperation: &framework.PathOperation{Callback: b.writePolicy},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.writePolicy},
+            logical.DeleteOperation: &framework.PathOperation{Callback: b.deletePolicy},
+        },
+    }
+}
+
+func pathPolicyDryRun(b *Backend) *framework.Path {
+    fields := mergeFields(tonMessageFields(), jettonTransferFields(), nftTransferFields(), map[string]*framework.FieldSchema{
+        "name":    {Type: framework.TypeString},
+        "version": versionField(),
+        "kind": {
+            Type:          framework.TypeString,
+            Description:   "Kind of transfer to evaluate, with the fields of its txn endpoint.",
+            Default:       txnKindTon,
+            AllowedValues: []interface{}{txnKindTon, txnKindJetton, txnKindNFT},
+        },
+        "messages": {
+            Type:        framework.TypeSlice,
+            Description: "(Optional) Batch of TON transfers, as for txn/ton/transfer.",
+        },
+        "entity_id": {
+            Type:        framework.TypeString,
+            Description: "(Optional) Caller entity to evaluate for. Defaults to the caller of this request.",
+        },
+        "time": {
+            Type:        framework.TypeString,
+            Description: "(Optional) RFC 3339 time to evaluate at. Defaults to now.",
+        },
+    })
+    // a draft policy may be evaluated instead of the stored one
+    for k, v := range policyFields() {
+        draft := *v
+        draft.Description = "(Optional) Draft policy: " + v.Description
+        fields[k] = &draft
+    }
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/policy/dry-run",
+        ExistenceCheck: b.pathExistenceCheck,
+        HelpSynopsis:   "Evaluate the signing policy against a transfer without signing it",
+        HelpDescription: `
+Takes kind and the fields of the matching txn endpoint and reports whether the policy allows the
+transfer, the rule that decided and the result of each rule evaluated. Pass rules to evaluate a
+draft policy instead of the stored one.
+        `,
+        Fields: fields,
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.CreateOperation: &framework.PathOperation{Callback: b.dryRunPolicy},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.dryRunPolicy},
+        },
+    }
+}
+
+// readPolicy handles GET key-managers/{name}/policy
+func (b *Backend) readPolicy(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    out := map[string]interface{}{"service_name": km.ServiceName}
+    if p := km.Policy; p != nil {
+        out["rules"] = p.Rules
+        out["default_effect"] = p.DefaultEffect
+        out["lists"] = p.Lists
+    }
+    return &logical.Response{Data: out}, nil
+}
+
+// writePolicy handles POST key-managers/{name}/policy
+func (b *Backend) writePolicy(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    p, err := parsePolicy(data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    if len(p.Rules) == 0 {
+        return logical.ErrorResponse("rules is required"), nil
+    }
+    km.Policy = p
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return b.readPolicy(ctx, req, data)
+}
+
+// deletePolicy handles DELETE key-managers/{name}/policy
+func (b *Backend) deletePolicy(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    km.Policy = nil
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return nil, nil
+}
+
+// dryRunPolicy handles POST key-managers/{name}/policy/dry-run
+func (b *Backend) dryRunPolicy(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    policy := km.Policy
+    if _, ok := data.GetOk("rules"); ok {
+        if policy, err = parsePolicy(data); err != nil {
+            return logical.ErrorResponse(err.Error()), nil
+        }
+    }
+    if policy == nil {
+        return logical.ErrorResponse("key-manager %q has no signing policy", km.ServiceName), nil
+    }
+
+    kp, err := km.signingKey(data.Get("version").(int))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    var msgs []*outMessage
+    switch data.Get("kind").(string) {
+    case txnKindJetton:
+        var msg *outMessage
+        if msg, err = jettonTransferMessage(data, km, kp); err == nil {
+            msgs = []*outMessage{msg}
+        }
+    case txnKindNFT:
+        var msg *outMessage
+        if msg, err = nftTransferMessage(data, kp); err == nil {
+            msgs = []*outMessage{msg}
+        }
+    default:
+        msgs, err = tonTransferMessages(data)
+    }
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    in := policyInput{
+        ServiceName: km.ServiceName,
+        KeyVersion:  kp.ID,
+        EntityID:    req.EntityID,
+        Now:         time.Now(),
+        Messages:    msgs,
+    }
+    if v, ok := data.GetOk("entity_id"); ok {
+        in.EntityID = v.(string)
+    }
+    if v := strings.TrimSpace(data.Get("time").(string)); v != "" {
+        if in.Now, err = time.Parse(time.RFC3339, v); err != nil {
+            return logical.ErrorResponse("invalid time: %s", err), nil
+        }
+    }
+    d, err := policy.evaluate(in)
+    if err != nil {
+        return nil, err
+    }
+    out := d.responseData()
+    previews := make([]map[string]interface{}, len(msgs))
+    for i, m := range msgs {
+        previews[i] = m.policyView()
+    }
+    out["messages"] = previews
+    return &logical.Response{Data: out}, nil
+}
+
+// parsePolicy reads and validates a policy from the request.
+func parsePolicy(data *framework.FieldData) (*signingPolicy, error) {
+    p := &signingPolicy{DefaultEffect: data.Get("default_effect").(string)}
+    raw, err := json.Marshal(data.Get("rules"))
+    if err != nil {
+        return nil, err
+    }
+    if err := json.Unmarshal(raw, &p.Rules); err != nil {
+        return nil, fmt.Errorf("rules must be a list of {name, effect, expression} objects: %w", err)
+    }
+    for name, v := range data.Get("lists").(map[string]interface{}) {
+        var addrs []string
+        switch v := v.(type) {
+        case string:
+            addrs = strings.Split(v, ",")
+        case []interface{}:
+            for _, a := range v {
+                addrs = append(addrs, fmt.Sprint(a))
+            }
+        default:
+            return nil, fmt.Errorf("list %q must be a list of addresses", name)
+        }
+        normalized, err := normalizeAddresses(addrs)
+        if err != nil {
+            return nil, fmt.Errorf("list %q: %w", name, err)
+        }
+        if p.Lists == nil {
+            p.Lists = make(map[string][]string)
+        }
+        p.Lists[name] = normalized
+    }
+    if err := p.validate(); err != nil {
+        return nil, err
+    }
+    return p, nil
+}
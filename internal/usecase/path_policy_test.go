@@ -0,0 +1,115 @@
+// internal/usecase/path_policy_test.go
+
+package usecase
+
+import (
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+const testKYCAddress = "0:3333333333333333333333333333333333333333333333333333333333333333"
+
+func TestSigningPolicy(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    doRequest(t, b, storage, logical.UpdateOperation, "key-managers", "", map[string]interface{}{"serviceName": "svc"})
+
+    // invalid expressions are refused
+    resp := doRequest(t, b, storage, logical.UpdateOperation, "key-managers/svc/policy", "", map[string]interface{}{
+        "rules": []interface{}{map[string]interface{}{"name": "bad", "effect": "allow", "expression": "messages.size()"}},
+    })
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "must evaluate to a bool")
+
+    rules := []interface{}{
+        map[string]interface{}{
+            "name":       "jettons-to-kyc-on-weekdays",
+            "effect":     "allow",
+            "expression": `messages.all(m, m.kind == "jetton" && m.recipient in lists.kyc) && now.getDayOfWeek("UTC") >= 1 && now.getDayOfWeek("UTC") <= 5`,
+        },
+        map[string]interface{}{
+            "name":       "gas-top-ups",
+            "effect":     "allow",
+            "expression": `messages.all(m, m.kind == "ton" && m.amount < 1000000000)`,
+        },
+    }
+    resp = doRequest(t, b, storage, logical.UpdateOperation, "key-managers/svc/policy", "", map[string]interface{}{
+        "rules": rules,
+        "lists": map[string]interface{}{"kyc": []interface{}{testKYCAddress}},
+    })
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, policyDeny, resp.Data["default_effect"])
+
+    transferTon := func(amount string) *logical.Response {
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/txn/ton/transfer", "", map[string]interface{}{
+            "destination": testDestination, "amount": amount, "seqno": 1,
+        })
+    }
+    assert.False(t, transferTon("500000000").IsError())
+    resp = transferTon("2000000000")
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "denied by signing policy")
+
+    dryRun := func(data map[string]interface{}) *logical.Response {
+        resp := doRequest(t, b, storage, logical.UpdateOperation, "key-managers/svc/policy/dry-run", "", data)
+        require.False(t, resp.IsError(), "%v", resp.Data)
+        return resp
+    }
+    jetton := func(recipient, at string) map[string]interface{} {
+        return map[string]interface{}{
+            "kind":         "jetton",
+            "jettonWallet": testDestination,
+            "destination":  recipient,
+            "amount":       "1000",
+            "time":         at,
+        }
+    }
+    // Wednesday
+    resp = dryRun(jetton(testKYCAddress, "2024-05-15T10:00:00Z"))
+    assert.Equal(t, true, resp.Data["allowed"])
+    assert.Equal(t, "jettons-to-kyc-on-weekdays", resp.Data["rule"])
+    // Saturday
+    resp = dryRun(jetton(testKYCAddress, "2024-05-18T10:00:00Z"))
+    assert.Equal(t, false, resp.Data["allowed"])
+    assert.Equal(t, "", resp.Data["rule"])
+    assert.Len(t, resp.Data["trace"], 2)
+    // not KYC'd
+    resp = dryRun(jetton(testDestination, "2024-05-15T10:00:00Z"))
+    assert.Equal(t, false, resp.Data["allowed"])
+
+    // a draft policy is evaluated instead of the stored one
+    resp = dryRun(map[string]interface{}{
+        "destination": testDestination,
+        "amount":      "2000000000",
+        "rules": []interface{}{map[string]interface{}{
+            "name": "deny-all-ton", "effect": "deny", "expression": `messages.exists(m, m.kind == "ton")`,
+        }},
+        "default_effect": "allow",
+    })
+    assert.Equal(t, false, resp.Data["allowed"])
+    assert.Equal(t, "deny-all-ton", resp.Data["rule"])
+
+    // rules see the send mode and bounce flag of each message
+    carryBalance := func(mode int) map[string]interface{} {
+        return map[string]interface{}{
+            "destination": testDestination,
+            "amount":      "0",
+            "mode":        mode,
+            "rules": []interface{}{map[string]interface{}{
+                "name": "deny-carry-balance", "effect": "deny", "expression": `messages.exists(m, m.mode >= 128 && m.bounce)`,
+            }},
+            "default_effect": "allow",
+        }
+    }
+    resp = dryRun(carryBalance(128))
+    assert.Equal(t, false, resp.Data["allowed"])
+    assert.Equal(t, "deny-carry-balance", resp.Data["rule"])
+    resp = dryRun(carryBalance(3))
+    assert.Equal(t, true, resp.Data["allowed"])
+
+    doRequest(t, b, storage, logical.DeleteOperation, "key-managers/svc/policy", "", nil)
+    assert.False(t, transferTon("2000000000").IsError())
+}
@@ -0,0 +1,145 @@
+// internal/usecase/path_rate_limit.go
+
+package usecase
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+func pathRateLimit(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/rate-limit",
+        ExistenceCheck: b.pathExistenceCheck,
+        HelpSynopsis:   "Manage the rate limits of the sign and txn paths of a key‑manager",
+        HelpDescription: `
+GET     — return the rate limits
+POST    — set token buckets: rate/burst for the whole key‑manager and caller_rate/caller_burst for
+          each caller, identified by entity or by token accessor; a zero rate is unlimited
+DELETE  — remove the rate limits
+Throttled requests fail with HTTP 429 and the time after which to retry. Buckets are kept in
+memory and, with persist, also written to storage on every request so they survive restarts.
+        `,
+        Fields: map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+            "rate": {
+                Type:        framework.TypeFloat,
+                Description: "Requests per second for the key‑manager across all callers.",
+            },
+            "burst": {
+                Type:        framework.TypeInt,
+                Description: "Requests the key‑manager accepts at once. Defaults to max(1, rate).",
+            },
+            "caller_rate": {
+                Type:        framework.TypeFloat,
+                Description: "Requests per second for each caller.",
+            },
+            "caller_burst": {
+                Type:        framework.TypeInt,
+                Description: "Requests each caller may send at once. Defaults to max(1, caller_rate).",
+            },
+            "persist": {
+                Type:        framework.TypeBool,
+                Description: "(Optional) Persist the buckets to storage.",
+            },
+        },
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.ReadOperation:   &framework.PathOperation{Callback: b.readRateLimit},
+            logical.CreateOperation: &framework.PathOperation{Callback: b.writeRateLimit},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.writeRateLimit},
+            logical.DeleteOperation: &framework.PathOperation{Callback: b.deleteRateLimit},
+        },
+    }
+}
+
+// readRateLimit handles GET key-managers/{name}/rate-limit
+func (b *Backend) readRateLimit(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    out := map[string]interface{}{"service_name": km.ServiceName}
+    if rl := km.RateLimit; rl != nil {
+        out["rate"] = rl.Rate
+        out["burst"] = rl.Burst
+        out["caller_rate"] = rl.CallerRate
+        out["caller_burst"] = rl.CallerBurst
+        out["persist"] = rl.Persist
+    }
+    return &logical.Response{Data: out}, nil
+}
+
+// writeRateLimit handles POST key-managers/{name}/rate-limit
+func (b *Backend) writeRateLimit(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    rl := &rateLimit{
+        Rate:        data.Get("rate").(float64),
+        Burst:       data.Get("burst").(int),
+        CallerRate:  data.Get("caller_rate").(float64),
+        CallerBurst: data.Get("caller_burst").(int),
+        Persist:     data.Get("persist").(bool),
+    }
+    if rl.Rate < 0 || rl.CallerRate < 0 || rl.Burst < 0 || rl.CallerBurst < 0 {
+        return logical.ErrorResponse("rates and bursts must not be negative"), nil
+    }
+    if rl.Rate == 0 && rl.CallerRate == 0 {
+        return logical.ErrorResponse("rate or caller_rate is required"), nil
+    }
+    rl.Burst = defaultBurst(rl.Rate, rl.Burst)
+    rl.CallerBurst = defaultBurst(rl.CallerRate, rl.CallerBurst)
+
+    km.RateLimit = rl
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return b.readRateLimit(ctx, req, data)
+}
+
+// deleteRateLimit handles DELETE key-managers/{name}/rate-limit
+func (b *Backend) deleteRateLimit(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    km.RateLimit = nil
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    if err := req.Storage.Delete(ctx, rateLimitPrefix+km.ServiceName); err != nil {
+        return nil, err
+    }
+    b.resetRateBuckets(km.ServiceName)
+    return nil, nil
+}
+
+// defaultBurst lets a bucket hold one second of requests, and at least one, unless set.
+func defaultBurst(rate float64, burst int) int {
+    if burst > 0 || rate == 0 {
+        return burst
+    }
+    if rate < 1 {
+        return 1
+    }
+    return int(rate)
+}
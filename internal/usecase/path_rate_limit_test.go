@@ -0,0 +1,71 @@
+// internal/usecase/path_rate_limit_test.go
+
+package usecase
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+const testHash = "af41db2300000000000000000000000000000000000000000000000000000023"
+
+func TestRateLimit(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "svc"}
+    _, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+
+    // a very slow refill so the test does not race the clock
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/svc/rate-limit")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"rate": 0.001, "burst": 3, "caller_rate": 0.001, "caller_burst": 2, "persist": true}
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+
+    sign := func(b *Backend, entity string) error {
+        req := logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/sign")
+        req.Storage = storage
+        req.EntityID = entity
+        req.Data = map[string]interface{}{"hash": testHash}
+        _, err := b.HandleRequest(context.Background(), req)
+        return err
+    }
+    require.NoError(t, sign(b, "alice"))
+    require.NoError(t, sign(b, "alice"))
+
+    // alice's bucket is empty, bob still has his own
+    err = sign(b, "alice")
+    require.Error(t, err)
+    var coded logical.HTTPCodedError
+    require.True(t, errors.As(err, &coded))
+    assert.Equal(t, http.StatusTooManyRequests, coded.Code())
+    assert.Contains(t, err.Error(), `caller "alice"`)
+
+    require.NoError(t, sign(b, "bob"))
+    // the key-manager bucket of 3 is now empty for everyone
+    err = sign(b, "carol")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), `key-manager "svc"`)
+
+    // persisted buckets survive a restart
+    restarted, _ := newTestBackend(t)
+    err = sign(restarted, "dave")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "rate limit exceeded")
+
+    req = logical.TestRequest(t, logical.DeleteOperation, "key-managers/svc/rate-limit")
+    req.Storage = storage
+    _, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.NoError(t, sign(b, "alice"))
+}
@@ -15,16 +15,22 @@ func pathReadAndDelete(b *Backend) *framework.Path {
     return &framework.Path{
         Pattern:        "key-managers/" + framework.GenericNameRegex("name"),
         ExistenceCheck: b.pathExistenceCheck,
-        HelpSynopsis:   "Read or delete a TON key‑manager by name",
+        HelpSynopsis:   "Read, update or delete a TON key‑manager by name",
         HelpDescription: `
-GET     — return the key‑manager details (addresses, public keys)
+GET     — return the key‑manager details (addresses, public keys, labels, metadata)
+POST    — replace the key‑manager labels and/or metadata, set min_signing_version
 DELETE  — remove the key‑manager and all its keys by name
         `,
-        Fields: map[string]*framework.FieldSchema{
+        Fields: mergeFields(map[string]*framework.FieldSchema{
             "name": {Type: framework.TypeString},
-        },
+            "min_signing_version": {
+                Type:        framework.TypeInt,
+                Description: "(Optional) Lowest key version still allowed to sign. Older versions can only verify.",
+            },
+        }, labelsFields("", "key‑manager")),
         Operations: map[logical.Operation]framework.OperationHandler{
             logical.ReadOperation:   &framework.PathOperation{Callback: b.readKeyManager},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.updateKeyManager},
             logical.DeleteOperation: &framework.PathOperation{Callback: b.deleteKeyManager},
         },
     }
@@ -55,12 +61,65 @@ func (b *Backend) readKeyManager(
 
     return &logical.Response{
         Data: map[string]interface{}{
-            "service_name": km.ServiceName,
-            "addresses":    addresses,
+            "service_name":        km.ServiceName,
+            "addresses":           addresses,
+            "labels":              km.Labels,
+            "metadata":            km.Metadata,
+            "current_version":     km.CurrentVersion,
+            "min_signing_version": km.MinSigningVersion,
         },
     }, nil
 }
 
+// updateKeyManager handles POST key‑managers/{name}
+func (b *Backend) updateKeyManager(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+
+    labels, setLabels, err := parseLabels(data, "labels")
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    metadata, setMetadata, err := parseMetadata(data, "metadata")
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil {
+        b.Logger().Error("Failed to retrieve key‑manager", "name", name, "error", err)
+        return nil, err
+    }
+    if km == nil {
+        return nil, fmt.Errorf("key‑manager %q not found", name)
+    }
+    if setLabels {
+        km.Labels = labels
+    }
+    if setMetadata {
+        km.Metadata = metadata
+    }
+    if v, ok := data.GetOk("min_signing_version"); ok {
+        minVersion := v.(int)
+        if minVersion < 0 || minVersion > km.CurrentVersion {
+            return logical.ErrorResponse("min_signing_version must be between 0 and the current version %d", km.CurrentVersion), nil
+        }
+        if minVersion > 0 {
+            if err := km.checkSingleKey(); err != nil {
+                return logical.ErrorResponse(err.Error()), nil
+            }
+        }
+        km.MinSigningVersion = minVersion
+    }
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return b.readKeyManager(ctx, req, data)
+}
+
 // deleteKeyManager handles DELETE key‑managers/{name}
 func (b *Backend) deleteKeyManager(
     ctx context.Context,
@@ -85,5 +144,27 @@ func (b *Backend) deleteKeyManager(
         b.Logger().Error("Failed to delete key‑manager", "path", path, "error", err)
         return nil, err
     }
+    for _, kp := range km.KeyPairs {
+        if err := b.unindexKeyPair(ctx, req.Storage, km.ServiceName, kp); err != nil {
+            return nil, err
+        }
+    }
+    if err := req.Storage.Delete(ctx, spendingPrefix+km.ServiceName); err != nil {
+        b.Logger().Error("Failed to delete spend ledger", "name", name, "error", err)
+        return nil, err
+    }
+    if err := logical.ClearView(ctx, logical.NewStorageView(req.Storage, pendingKey(km.ServiceName, ""))); err != nil {
+        b.Logger().Error("Failed to delete pending requests", "name", name, "error", err)
+        return nil, err
+    }
+    if err := req.Storage.Delete(ctx, rateLimitPrefix+km.ServiceName); err != nil {
+        b.Logger().Error("Failed to delete rate limit buckets", "name", name, "error", err)
+        return nil, err
+    }
+    if err := logical.ClearView(ctx, logical.NewStorageView(req.Storage, idempotencyPrefix+km.ServiceName+"/")); err != nil {
+        b.Logger().Error("Failed to delete idempotency records", "name", name, "error", err)
+        return nil, err
+    }
+    b.resetRateBuckets(km.ServiceName)
     return nil, nil
 }
@@ -0,0 +1,97 @@
+// internal/usecase/path_rotate.go
+
+package usecase
+
+import (
+    "context"
+    "crypto/ed25519"
+    "crypto/rand"
+    "fmt"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+func pathRotate(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/rotate",
+        ExistenceCheck: b.pathExistenceCheck,
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.CreateOperation: &framework.PathOperation{Callback: b.rotateKeyManager},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.rotateKeyManager},
+        },
+        HelpSynopsis:    "Rotate the signing key of a TON key‑manager.",
+        HelpDescription: "POST generates a new key version and makes it the current signing key. Older versions stay readable and usable for verification. Only key-managers holding a single independent key can be rotated. The new version inherits the exportable flag and wallet contract of the current one.",
+        Fields: map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+        },
+    }
+}
+
+func (b *Backend) rotateKeyManager(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    if err := km.checkSingleKey(); err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    cfg, err := b.config(ctx, req.Storage)
+    if err != nil {
+        return nil, err
+    }
+    if err := b.checkKeyLimits(ctx, req, cfg, km, false); err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    // the new version keeps the wallet contract of the current one
+    ws, err := cfg.walletSettings()
+    if err != nil {
+        return nil, err
+    }
+    current := km.keyPairByID(km.CurrentVersion)
+    if current != nil {
+        if ws, err = current.walletSettings(); err != nil {
+            return nil, err
+        }
+    }
+
+    seed := make([]byte, ed25519.SeedSize)
+    if _, err := rand.Read(seed); err != nil {
+        return nil, fmt.Errorf("failed to generate seed: %w", err)
+    }
+    defer zeroSeed(seed)
+
+    kp, err := newKeyPair(seed, ws)
+    if err != nil {
+        return nil, err
+    }
+    if current != nil {
+        kp.Exportable = current.Exportable
+    }
+    if err := km.rotate(kp); err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    if err := b.indexKeyPair(ctx, req.Storage, km.ServiceName, kp); err != nil {
+        return nil, err
+    }
+
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "service_name":        km.ServiceName,
+            "version":             kp.ID,
+            "address":             kp.Address,
+            "public_key":          kp.PublicKey,
+            "min_signing_version": km.MinSigningVersion,
+        },
+    }, nil
+}
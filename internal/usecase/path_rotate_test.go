@@ -0,0 +1,107 @@
+// internal/usecase/path_rotate_test.go
+
+package usecase
+
+import (
+    "context"
+    "encoding/hex"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestRotateAndMinSigningVersion(t *testing.T) {
+    b, storage := newTestBackend(t)
+    zeroHash := hex.EncodeToString(make([]byte, 32))
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "svc"}
+    _, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+
+    // Rotate to version 2
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/rotate")
+    req.Storage = storage
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, 2, resp.Data["version"])
+
+    // Sign defaults to the current version
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/sign")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"hash": zeroHash}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, 2, resp.Data["version"])
+
+    // Old version can still sign until min_signing_version is raised
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/sign")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"hash": zeroHash, "version": 1}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    oldSig := resp.Data["signature"].(string)
+
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers/svc")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"min_signing_version": 2}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, 2, resp.Data["min_signing_version"])
+
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/sign")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"hash": zeroHash, "version": 1}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.True(t, resp.IsError())
+
+    // ...but keeps verifying
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/verify")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"hash": zeroHash, "signature": oldSig, "version": 1}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, true, resp.Data["valid"])
+
+    req = logical.TestRequest(t, logical.ListOperation, "key-managers/svc/keys")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"status": keyStatusDisabled}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"1"}, resp.Data["keys"])
+}
+
+func TestRotateSingleKeyOnly(t *testing.T) {
+    b, storage := newTestBackend(t)
+    write := func(path string, data map[string]interface{}) *logical.Response {
+        return doRequest(t, b, storage, logical.UpdateOperation, path, "", data)
+    }
+
+    // a key-manager holding several deposit addresses has no versions to rotate or retire
+    require.False(t, write("key-managers", map[string]interface{}{"serviceName": "deposits"}).IsError())
+    require.False(t, write("key-managers", map[string]interface{}{"serviceName": "deposits"}).IsError())
+    resp := write("key-managers/deposits/rotate", nil)
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "independent keys")
+    resp = write("key-managers/deposits", map[string]interface{}{"min_signing_version": 1})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "independent keys")
+
+    // a rotated key-manager takes no independent keys
+    require.False(t, write("key-managers", map[string]interface{}{"serviceName": "hot"}).IsError())
+    resp = write("key-managers/hot/rotate", nil)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    resp = write("key-managers", map[string]interface{}{"serviceName": "hot"})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "versioned by rotation")
+
+    req := logical.TestRequest(t, logical.ReadOperation, "key-managers/hot/keys/2")
+    req.Storage = storage
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, 1, resp.Data["rotated_from"])
+}
@@ -22,17 +22,54 @@ func pathSign(b *Backend) *framework.Path {
             },
         },
         HelpSynopsis:    "Sign a 32‑byte SHA256 hash with a TON Ed25519 key.",
-        HelpDescription: "POST name, hash(hex‑encoded SHA256) → signature(hex‑encoded Ed25519).",
+        HelpDescription: "POST name, hash(hex‑encoded SHA256) → signature(hex‑encoded Ed25519), version.",
         Fields: map[string]*framework.FieldSchema{
             "name": {Type: framework.TypeString},
             "hash": {
                 Type:        framework.TypeString,
                 Description: "Hex‑encoded 32‑byte SHA256 hash to sign.",
             },
+            "version": versionField(),
         },
     }
 }
 
+func pathVerify(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/verify",
+        ExistenceCheck: b.pathExistenceCheck,
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.CreateOperation: &framework.PathOperation{Callback: b.verifyHash},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.verifyHash},
+        },
+        HelpSynopsis:    "Verify an Ed25519 signature made by any key version.",
+        HelpDescription: "POST name, hash, signature, version → valid. Versions below min_signing_version can still verify.",
+        Fields: map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+            "hash": {
+                Type:        framework.TypeString,
+                Description: "Hex‑encoded 32‑byte SHA256 hash that was signed.",
+            },
+            "signature": {
+                Type:        framework.TypeString,
+                Description: "Hex‑encoded Ed25519 signature.",
+            },
+            "version": {
+                Type:        framework.TypeInt,
+                Description: "(Optional) Key version that made the signature. Defaults to the current version.",
+            },
+        },
+    }
+}
+
+// versionField is the schema of the key version selector of the signing endpoints.
+func versionField() *framework.FieldSchema {
+    return &framework.FieldSchema{
+        Type:        framework.TypeInt,
+        Description: "(Optional) Key version to sign with. Defaults to the current version of the key‑manager.",
+    }
+}
+
 func (b *Backend) signHash(
     ctx context.Context,
     req *logical.Request,
@@ -41,18 +78,23 @@ func (b *Backend) signHash(
     name := data.Get("name").(string)
     hashHex := data.Get("hash").(string)
 
-    // 1) Load KeyManager and seed
+    // 1) Load KeyManager and the signing key
     km, err := b.retrieveKeyManager(ctx, req, name)
     if err != nil || km == nil {
         return nil, fmt.Errorf("key-manager %q not found", name)
     }
-    seed, err := hex.DecodeString(km.KeyPairs[0].PrivateKey)
+    if err := b.throttle(ctx, req, km); err != nil {
+        return nil, err
+    }
+    kp, err := km.signingKey(data.Get("version").(int))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    priv, err := kp.privateKey()
     if err != nil {
-        return nil, fmt.Errorf("invalid stored seed hex: %w", err)
+        return nil, err
     }
-    // derive full private key
-    priv := ed25519.NewKeyFromSeed(seed)
-    defer zeroSeed(seed) // wipe seed
+    defer zeroSeed(priv) // wipe key
 
     // 2) Decode the hash
     hashBytes, err := hex.DecodeString(hashHex)
@@ -66,9 +108,56 @@ func (b *Backend) signHash(
     // 3) Sign
     sig := ed25519.Sign(priv, hashBytes)
 
+    // 4) Record it in the history
+    if err := b.recordHistory(ctx, req, km, kp, &historyEntry{Hash: hex.EncodeToString(hashBytes)}); err != nil {
+        return nil, err
+    }
+
     return &logical.Response{
         Data: map[string]interface{}{
             "signature": hex.EncodeToString(sig),
+            "version":   kp.ID,
+        },
+    }, nil
+}
+
+func (b *Backend) verifyHash(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    version := data.Get("version").(int)
+    if version == 0 {
+        version = km.CurrentVersion
+    }
+    kp := km.keyPairByID(version)
+    if kp == nil {
+        return logical.ErrorResponse("key version %d not found in key-manager %q", version, name), nil
+    }
+    pub, err := hex.DecodeString(kp.PublicKey)
+    if err != nil {
+        return nil, fmt.Errorf("invalid stored public key hex: %w", err)
+    }
+
+    hashBytes, err := hex.DecodeString(data.Get("hash").(string))
+    if err != nil || len(hashBytes) != 32 {
+        return logical.ErrorResponse("hash must be 32-byte hex"), nil
+    }
+    sig, err := hex.DecodeString(data.Get("signature").(string))
+    if err != nil || len(sig) != ed25519.SignatureSize {
+        return logical.ErrorResponse("signature must be %d-byte hex", ed25519.SignatureSize), nil
+    }
+
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "valid":   ed25519.Verify(pub, hashBytes, sig),
+            "version": kp.ID,
         },
     }, nil
 }
@@ -0,0 +1,174 @@
+// internal/usecase/path_sign_data.go
+
+package usecase
+
+import (
+    "context"
+    "crypto/ed25519"
+    "encoding/base64"
+    "encoding/hex"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/ton"
+)
+
+func pathSignData(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/sign-data",
+        ExistenceCheck: b.pathExistenceCheck,
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.CreateOperation: &framework.PathOperation{Callback: b.signData},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.signData},
+        },
+        HelpSynopsis: "Answer a TON Connect signData request with the key‑manager wallet",
+        HelpDescription: `
+POST type=text (text), type=binary (bytes, base64) or type=cell (cell as base64 BOC and its TL-B
+schema), domain and timestamp → signature (base64), address (raw), timestamp, domain and the payload.
+Text and binary payloads are signed over sha256 of the "ton-connect/sign-data/" message, cell
+payloads over the hash of the 0x75569022 cell carrying crc32(schema), as the TON Connect spec defines.
+        `,
+        Fields: map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+            "type": {
+                Type:          framework.TypeString,
+                Description:   "Payload type: text, binary or cell.",
+                AllowedValues: []interface{}{signDataText, signDataBinary, signDataCell},
+            },
+            "text": {
+                Type:        framework.TypeString,
+                Description: "Text to sign, for type=text.",
+            },
+            "bytes": {
+                Type:        framework.TypeString,
+                Description: "Base64 data to sign, for type=binary.",
+            },
+            "cell": {
+                Type:        framework.TypeString,
+                Description: "Base64 BOC of the cell to sign, for type=cell.",
+            },
+            "schema": {
+                Type:        framework.TypeString,
+                Description: "TL-B schema of the cell, for type=cell.",
+            },
+            "domain": {
+                Type:        framework.TypeString,
+                Description: "App domain the data is signed for, e.g. example.com.",
+            },
+            "timestamp": {
+                Type:        framework.TypeInt,
+                Description: "(Optional) Unix time of the signature. Defaults to now.",
+            },
+            "version": versionField(),
+        },
+    }
+}
+
+// signData handles POST key-managers/{name}/sign-data
+func (b *Backend) signData(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    payloadType := data.Get("type").(string)
+    domain := strings.TrimSpace(data.Get("domain").(string))
+    if domain == "" {
+        return logical.ErrorResponse("domain is required"), nil
+    }
+    timestamp := time.Now().Unix()
+    if v, ok := data.GetOk("timestamp"); ok {
+        if timestamp = int64(v.(int)); timestamp <= 0 {
+            return logical.ErrorResponse("timestamp must be positive"), nil
+        }
+    }
+
+    // decode the payload before touching the key
+    payload := map[string]interface{}{"type": payloadType}
+    var raw []byte
+    var cell *boc.Cell
+    switch payloadType {
+    case signDataText:
+        text, ok := data.GetOk("text")
+        if !ok {
+            return logical.ErrorResponse("text is required for type=text"), nil
+        }
+        raw = []byte(text.(string))
+        payload["text"] = text
+    case signDataBinary:
+        s := data.Get("bytes").(string)
+        if s == "" {
+            return logical.ErrorResponse("bytes is required for type=binary"), nil
+        }
+        var err error
+        if raw, err = base64.StdEncoding.DecodeString(s); err != nil {
+            return logical.ErrorResponse("invalid base64 bytes: %v", err), nil
+        }
+        payload["bytes"] = s
+    case signDataCell:
+        s, schema := data.Get("cell").(string), data.Get("schema").(string)
+        if s == "" || schema == "" {
+            return logical.ErrorResponse("cell and schema are required for type=cell"), nil
+        }
+        cells, err := boc.DeserializeBocBase64(s)
+        if err != nil || len(cells) != 1 {
+            return logical.ErrorResponse("cell must be a base64 BOC with one root"), nil
+        }
+        cell = cells[0]
+        payload["cell"] = s
+        payload["schema"] = schema
+    default:
+        return logical.ErrorResponse("type must be %q, %q or %q", signDataText, signDataBinary, signDataCell), nil
+    }
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    if err := b.throttle(ctx, req, km); err != nil {
+        return nil, err
+    }
+    kp, err := km.signingKey(data.Get("version").(int))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    addr, err := ton.ParseAccountID(kp.Address)
+    if err != nil {
+        return nil, fmt.Errorf("invalid stored address %q: %w", kp.Address, err)
+    }
+    priv, err := kp.privateKey()
+    if err != nil {
+        return nil, err
+    }
+    defer zeroSeed(priv)
+
+    var hash []byte
+    if cell != nil {
+        if hash, err = signDataCellHash(addr, domain, timestamp, payload["schema"].(string), cell); err != nil {
+            return nil, fmt.Errorf("failed to build sign-data cell: %w", err)
+        }
+    } else {
+        hash = signDataHash(addr, domain, timestamp, payloadType, raw)
+    }
+    sig := ed25519.Sign(priv, hash)
+
+    if err := b.recordHistory(ctx, req, km, kp, &historyEntry{Hash: hex.EncodeToString(hash)}); err != nil {
+        return nil, err
+    }
+
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "signature":  base64.StdEncoding.EncodeToString(sig),
+            "address":    addr.ToRaw(),
+            "public_key": kp.PublicKey,
+            "timestamp":  timestamp,
+            "domain":     domain,
+            "payload":    payload,
+            "version":    kp.ID,
+        },
+    }, nil
+}
@@ -0,0 +1,115 @@
+// internal/usecase/path_sign_data_test.go
+
+package usecase
+
+import (
+    "bytes"
+    "context"
+    "crypto/ed25519"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/binary"
+    "encoding/hex"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/ton"
+)
+
+func TestSignData(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "svc"}
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+
+    km, err := b.retrieveKeyManager(context.Background(), req, "svc")
+    require.NoError(t, err)
+    kp := km.keyPairByID(km.CurrentVersion)
+    addr, err := ton.ParseAccountID(kp.Address)
+    require.NoError(t, err)
+    pub, err := hex.DecodeString(kp.PublicKey)
+    require.NoError(t, err)
+
+    signData := func(data map[string]interface{}) *logical.Response {
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/svc/sign-data", "", data)
+    }
+    signature := func(resp *logical.Response) []byte {
+        sig, err := base64.StdEncoding.DecodeString(resp.Data["signature"].(string))
+        require.NoError(t, err)
+        return sig
+    }
+    // the spec message, built independently of signDataHash
+    spec := func(prefix string, payload []byte) []byte {
+        var m bytes.Buffer
+        m.Write([]byte{0xff, 0xff})
+        m.WriteString("ton-connect/sign-data/")
+        binary.Write(&m, binary.BigEndian, int32(addr.Workchain))
+        m.Write(addr.Address[:])
+        binary.Write(&m, binary.BigEndian, uint32(len("example.com")))
+        m.WriteString("example.com")
+        binary.Write(&m, binary.BigEndian, uint64(1700000000))
+        m.WriteString(prefix)
+        binary.Write(&m, binary.BigEndian, uint32(len(payload)))
+        m.Write(payload)
+        sum := sha256.Sum256(m.Bytes())
+        return sum[:]
+    }
+
+    resp = signData(map[string]interface{}{"type": "text", "text": "Confirm login", "domain": "example.com", "timestamp": 1700000000})
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, addr.ToRaw(), resp.Data["address"])
+    assert.Equal(t, "example.com", resp.Data["domain"])
+    assert.EqualValues(t, 1700000000, resp.Data["timestamp"])
+    assert.True(t, ed25519.Verify(pub, spec("txt", []byte("Confirm login")), signature(resp)))
+
+    bin := []byte{0x01, 0x02, 0x03}
+    resp = signData(map[string]interface{}{"type": "binary", "bytes": base64.StdEncoding.EncodeToString(bin), "domain": "example.com", "timestamp": 1700000000})
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.True(t, ed25519.Verify(pub, spec("bin", bin), signature(resp)))
+
+    payload := boc.NewCell()
+    require.NoError(t, payload.WriteUint(42, 32))
+    payloadBoc, err := payload.ToBocBase64()
+    require.NoError(t, err)
+    schema := "message#_ value:uint32 = Message;"
+    resp = signData(map[string]interface{}{"type": "cell", "cell": payloadBoc, "schema": schema, "domain": "example.com", "timestamp": 1700000000})
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    hash, err := signDataCellHash(addr, "example.com", 1700000000, schema, payload)
+    require.NoError(t, err)
+    assert.True(t, ed25519.Verify(pub, hash, signature(resp)))
+    // the schema is part of what is signed
+    other, err := signDataCellHash(addr, "example.com", 1700000000, "other#_ value:uint32 = Other;", payload)
+    require.NoError(t, err)
+    assert.False(t, ed25519.Verify(pub, other, signature(resp)))
+
+    resp = signData(map[string]interface{}{"type": "cell", "cell": payloadBoc, "domain": "example.com"})
+    assert.True(t, resp.IsError())
+    resp = signData(map[string]interface{}{"type": "binary", "bytes": "%%%", "domain": "example.com"})
+    assert.True(t, resp.IsError())
+    resp = signData(map[string]interface{}{"type": "text", "text": "x"})
+    assert.True(t, resp.IsError())
+}
+
+func TestSignDataCellEncoding(t *testing.T) {
+    assert.Equal(t, []byte("com\x00example\x00"), encodeDomainDNS("example.com"))
+
+    long := strings.Repeat("a", 300)
+    c, err := snakeCell([]byte(long))
+    require.NoError(t, err)
+    var got []byte
+    for c != nil {
+        chunk, err := c.ReadBytes(c.BitsAvailableForRead() / 8)
+        require.NoError(t, err)
+        got = append(got, chunk...)
+        c, _ = c.NextRef()
+    }
+    assert.Equal(t, long, string(got))
+}
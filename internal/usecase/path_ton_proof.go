@@ -0,0 +1,118 @@
+// internal/usecase/path_ton_proof.go
+
+package usecase
+
+import (
+    "context"
+    "crypto/ed25519"
+    "encoding/base64"
+    "encoding/hex"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+func pathTonProof(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/ton-proof",
+        ExistenceCheck: b.pathExistenceCheck,
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.CreateOperation: &framework.PathOperation{Callback: b.signTonProof},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.signTonProof},
+        },
+        HelpSynopsis: "Sign a TON Connect ton_proof for the key‑manager wallet",
+        HelpDescription: `
+POST domain, payload, timestamp → the ton_proof of the wallet of the selected key: address (raw),
+public_key, state_init (base64 BOC), network chain id, timestamp, domain {lengthBytes, value},
+payload and signature (base64), ready to be sent to the verifier of a dApp.
+        `,
+        Fields: map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+            "domain": {
+                Type:        framework.TypeString,
+                Description: "App domain the proof is for, e.g. example.com.",
+            },
+            "payload": {
+                Type:        framework.TypeString,
+                Description: "Payload (challenge) issued by the verifier.",
+            },
+            "timestamp": {
+                Type:        framework.TypeInt,
+                Description: "(Optional) Unix time of the proof. Defaults to now.",
+            },
+            "version": versionField(),
+        },
+    }
+}
+
+// signTonProof handles POST key-managers/{name}/ton-proof
+func (b *Backend) signTonProof(
+    ctx context.Context,
+    req *logical.Request,
+    data *framework.FieldData,
+) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    domain := strings.TrimSpace(data.Get("domain").(string))
+    if domain == "" {
+        return logical.ErrorResponse("domain is required"), nil
+    }
+    payload := data.Get("payload").(string)
+    timestamp := time.Now().Unix()
+    if v, ok := data.GetOk("timestamp"); ok {
+        if timestamp = int64(v.(int)); timestamp <= 0 {
+            return logical.ErrorResponse("timestamp must be positive"), nil
+        }
+    }
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    if err := b.throttle(ctx, req, km); err != nil {
+        return nil, err
+    }
+    kp, err := km.signingKey(data.Get("version").(int))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    ws, err := kp.walletSettings()
+    if err != nil {
+        return nil, err
+    }
+    priv, err := kp.privateKey()
+    if err != nil {
+        return nil, err
+    }
+    defer zeroSeed(priv)
+
+    addr, stateInit, err := walletStateInit(priv, ws)
+    if err != nil {
+        return nil, err
+    }
+    hash := tonProofHash(addr, domain, timestamp, payload)
+    sig := ed25519.Sign(priv, hash)
+
+    if err := b.recordHistory(ctx, req, km, kp, &historyEntry{Hash: hex.EncodeToString(hash)}); err != nil {
+        return nil, err
+    }
+
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "address":    addr.ToRaw(),
+            "public_key": kp.PublicKey,
+            "state_init": stateInit,
+            "network":    tonConnectChain(ws.Network),
+            "timestamp":  timestamp,
+            "domain": map[string]interface{}{
+                "lengthBytes": len(domain),
+                "value":       domain,
+            },
+            "payload":   payload,
+            "signature": base64.StdEncoding.EncodeToString(sig),
+            "version":   kp.ID,
+        },
+    }, nil
+}
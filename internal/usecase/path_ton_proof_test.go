@@ -0,0 +1,79 @@
+// internal/usecase/path_ton_proof_test.go
+
+package usecase
+
+import (
+    "context"
+    "crypto/ed25519"
+    "encoding/base64"
+    "encoding/hex"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/tonconnect"
+)
+
+func TestTonProof(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"serviceName": "svc"}
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+
+    req = logical.TestRequest(t, logical.CreateOperation, "key-managers/svc/ton-proof")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"domain": "ton.org", "payload": "challenge-42", "timestamp": 1700000000}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, "-239", resp.Data["network"])
+    assert.EqualValues(t, 1700000000, resp.Data["timestamp"])
+    assert.Equal(t, map[string]interface{}{"lengthBytes": 7, "value": "ton.org"}, resp.Data["domain"])
+
+    km, err := b.retrieveKeyManager(context.Background(), req, "svc")
+    require.NoError(t, err)
+    kp := km.keyPairByID(km.CurrentVersion)
+    wantAddr, err := ton.ParseAccountID(kp.Address)
+    require.NoError(t, err)
+    assert.Equal(t, wantAddr.ToRaw(), resp.Data["address"])
+
+    // the state init deploys the wallet at the proved address
+    cells, err := boc.DeserializeBocBase64(resp.Data["state_init"].(string))
+    require.NoError(t, err)
+    hash, err := cells[0].Hash256()
+    require.NoError(t, err)
+    assert.Equal(t, wantAddr.Address, hash)
+
+    // same proof as the tongo reference implementation
+    priv, err := kp.privateKey()
+    require.NoError(t, err)
+    var init tlb.StateInit
+    require.NoError(t, tlb.Unmarshal(cells[0], &init))
+    ref, err := tonconnect.CreateSignedProof("challenge-42", wantAddr, priv, init, tonconnect.ProofOptions{
+        Timestamp: time.Unix(1700000000, 0),
+        Domain:    "ton.org",
+    })
+    require.NoError(t, err)
+    assert.Equal(t, ref.Proof.Signature, resp.Data["signature"])
+    assert.Equal(t, ref.Proof.StateInit, resp.Data["state_init"])
+
+    pub, err := hex.DecodeString(kp.PublicKey)
+    require.NoError(t, err)
+    sig, err := base64.StdEncoding.DecodeString(resp.Data["signature"].(string))
+    require.NoError(t, err)
+    assert.True(t, ed25519.Verify(pub, tonProofHash(wantAddr, "ton.org", 1700000000, "challenge-42"), sig))
+
+    req.Data = map[string]interface{}{"payload": "x"}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.True(t, resp.IsError())
+}
@@ -3,15 +3,19 @@ package usecase
 
 import (
     "context"
-    "crypto/sha256"
-    "encoding/base64"
-    "encoding/hex"
     "fmt"
+    "strings"
 
     "github.com/hashicorp/vault/sdk/framework"
     "github.com/hashicorp/vault/sdk/logical"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/wallet"
 )
 
+// defaultJettonTonAmount is attached to the jetton wallet to pay for the transfer chain.
+const defaultJettonTonAmount = "50000000"
+
 func pathTransferJetton(b *Backend) *framework.Path {
     return &framework.Path{
         Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/txn/jetton/transfer",
@@ -19,32 +23,199 @@ func pathTransferJetton(b *Backend) *framework.Path {
         Operations: map[logical.Operation]framework.OperationHandler{
             logical.CreateOperation: &framework.PathOperation{Callback: b.transferJetton},
         },
-        HelpSynopsis:    "Dummy Jetton transfer for tests",
-        HelpDescription: "↪ returns base64(seed) and sha256(seed)",
-        Fields: map[string]*framework.FieldSchema{
-            "name":         {Type: framework.TypeString},
-            "jettonWallet": {Type: framework.TypeString},
+        HelpSynopsis:    "Sign a jetton transfer from the key‑manager wallet",
+        HelpDescription: "↪ returns the signed external message as base64 BOC (signed_boc) and its hash (msg_id)",
+        Fields: txnFields(jettonTransferFields()),
+    }
+}
+
+// jettonTransferFields returns the schema of one jetton transfer.
+func jettonTransferFields() map[string]*framework.FieldSchema {
+    return map[string]*framework.FieldSchema{
+        "jettonWallet": {
+            Type:        framework.TypeString,
+            Description: "Jetton wallet of the sender that receives the transfer request. Optional for allowlisted masters, where it is computed.",
+        },
+        "jetton_master": {
+            Type:        framework.TypeString,
+            Description: "(Optional) Jetton master of the transfer, which must be allowlisted so that the jetton wallet can be computed. Required when the key‑manager has a jetton allowlist.",
+        },
+        "destination": {
+            Type:        framework.TypeString,
+            Description: "Owner address of the recipient.",
+        },
+        "amount": {
+            Type:        framework.TypeString,
+            Description: "Jetton amount in base units.",
+        },
+        "ton_amount": {
+            Type:        framework.TypeString,
+            Description: "(Optional) Nanotons attached to the jetton wallet for fees.",
+            Default:     defaultJettonTonAmount,
+        },
+        "forward_ton_amount": {
+            Type:        framework.TypeString,
+            Description: "(Optional) Nanotons forwarded to the recipient with the transfer notification.",
+            Default:     "0",
+        },
+        "response_destination": {
+            Type:        framework.TypeString,
+            Description: "(Optional) Address receiving the excess. Defaults to the sender wallet.",
+        },
+        "comment": {
+            Type:        framework.TypeString,
+            Description: "(Optional) Text comment forwarded to the recipient.",
+        },
+        "query_id": {
+            Type:        framework.TypeInt64,
+            Description: "(Optional) Query id of the transfer.",
         },
     }
 }
 
 func (b *Backend) transferJetton(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
     name := data.Get("name").(string)
+    params, err := parseTxnParams(data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
     km, err := b.retrieveKeyManager(ctx, req, name)
     if err != nil || km == nil {
         return nil, fmt.Errorf("key-manager %q not found", name)
     }
-    seed, err := hex.DecodeString(km.KeyPairs[0].PrivateKey)
+    kp, err := km.signingKey(data.Get("version").(int))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    msg, err := jettonTransferMessage(data, km, kp)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    return b.signTransfers(ctx, req, km, kp, params, []*outMessage{msg})
+}
+
+// jettonTransferMessage builds a jetton transfer request to the sender's jetton wallet.
+// When the key‑manager allowlists jetton masters, the master must be one of them and
+// the jetton wallet is computed from it rather than trusted from the request.
+func jettonTransferMessage(data *framework.FieldData, km *KeyManager, kp *KeyPair) (*outMessage, error) {
+    var master *ton.AccountID
+    if strings.TrimSpace(data.Get("jetton_master").(string)) != "" {
+        id, _, err := parseDestination(data, "jetton_master", "")
+        if err != nil {
+            return nil, err
+        }
+        master = &id
+    }
+    jettonWallet, err := senderJettonWallet(data, km, kp, master)
+    if err != nil {
+        return nil, err
+    }
+    recipient, _, err := parseDestination(data, "destination", "")
+    if err != nil {
+        return nil, err
+    }
+    amount, err := parseAmount(data.Get("amount").(string), "amount")
+    if err != nil {
+        return nil, err
+    }
+    tonAmount, err := parseNanotons(data.Get("ton_amount").(string), "ton_amount")
     if err != nil {
-        return nil, fmt.Errorf("invalid seed hex: %w", err)
+        return nil, err
     }
-    boc := seed
-    bocB64 := base64.StdEncoding.EncodeToString(boc)
-    id := sha256.Sum256(boc)
-    return &logical.Response{
-        Data: map[string]interface{}{
-            "signed_boc": bocB64,
-            "msg_id":     hex.EncodeToString(id[:]),
+    forwardTon, err := parseNanotons(data.Get("forward_ton_amount").(string), "forward_ton_amount")
+    if err != nil {
+        return nil, err
+    }
+    response, err := ton.ParseAccountID(kp.Address)
+    if err != nil {
+        return nil, err
+    }
+    if strings.TrimSpace(data.Get("response_destination").(string)) != "" {
+        if response, _, err = parseDestination(data, "response_destination", ""); err != nil {
+            return nil, err
+        }
+    }
+    excess, err := responseDestination(response, kp)
+    if err != nil {
+        return nil, err
+    }
+    comment := data.Get("comment").(string)
+
+    body, err := jettonTransferBody(uint64(data.Get("query_id").(int64)), amount, recipient, response, forwardTon, comment)
+    if err != nil {
+        return nil, fmt.Errorf("failed to build jetton transfer: %w", err)
+    }
+    return &outMessage{
+        Kind:         txnKindJetton,
+        Destination:  jettonWallet,
+        Amount:       tonAmount,
+        Comment:      comment,
+        JettonAmount: amount,
+        JettonMaster: master,
+        Recipient:    &recipient,
+
+        ResponseDestination: excess,
+        message: wallet.Message{
+            Amount:  tlb.Grams(tonAmount),
+            Address: jettonWallet,
+            Body:    body,
+            Bounce:  true,
+            Mode:    wallet.DefaultMessageMode,
         },
     }, nil
 }
+
+// responseDestination returns the excess address of a transfer, or nil when the
+// excess returns to the sender wallet.
+func responseDestination(response ton.AccountID, kp *KeyPair) (*ton.AccountID, error) {
+    sender, err := ton.ParseAccountID(kp.Address)
+    if err != nil {
+        return nil, err
+    }
+    if response == sender {
+        return nil, nil
+    }
+    return &response, nil
+}
+
+// senderJettonWallet returns the jetton wallet a transfer is sent to. A jetton
+// master is only accepted when its wallet code is known, so that limits keyed
+// on the master cannot be dodged by pairing it with the wallet of another jetton.
+func senderJettonWallet(data *framework.FieldData, km *KeyManager, kp *KeyPair, master *ton.AccountID) (ton.AccountID, error) {
+    given := strings.TrimSpace(data.Get("jettonWallet").(string)) != ""
+    if master == nil {
+        if len(km.Jettons) > 0 {
+            return ton.AccountID{}, fmt.Errorf("jetton_master is required: key-manager %q only sends allowlisted jettons", km.ServiceName)
+        }
+        id, _, err := parseDestination(data, "jettonWallet", "")
+        return id, err
+    }
+
+    j := km.Jettons[master.ToRaw()]
+    if j == nil && len(km.Jettons) == 0 {
+        return ton.AccountID{}, fmt.Errorf("jetton master %s cannot be verified: allowlist it with its wallet code or omit jetton_master", master.ToRaw())
+    }
+    if j == nil {
+        return ton.AccountID{}, fmt.Errorf("jetton master %s is not allowlisted for key-manager %q", master.ToRaw(), km.ServiceName)
+    }
+    owner, err := ton.ParseAccountID(kp.Address)
+    if err != nil {
+        return ton.AccountID{}, err
+    }
+    computed, err := j.walletAddress(*master, owner)
+    if err != nil {
+        return ton.AccountID{}, fmt.Errorf("failed to compute jetton wallet: %w", err)
+    }
+    if given {
+        id, _, err := parseDestination(data, "jettonWallet", "")
+        if err != nil {
+            return ton.AccountID{}, err
+        }
+        if id != computed {
+            return ton.AccountID{}, fmt.Errorf("jettonWallet %s is not the jetton wallet of the key for master %s, expected %s",
+                id.ToRaw(), master.ToRaw(), computed.ToRaw())
+        }
+    }
+    return computed, nil
+}
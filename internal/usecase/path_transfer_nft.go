@@ -0,0 +1,143 @@
+// internal/usecase/path_transfer_nft.go
+package usecase
+
+import (
+    "context"
+    "fmt"
+    "strings"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+// defaultNFTTonAmount is attached to the NFT item to pay for the transfer.
+const defaultNFTTonAmount = "50000000"
+
+func pathTransferNFT(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/txn/nft/transfer",
+        ExistenceCheck: b.pathExistenceCheck,
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.CreateOperation: &framework.PathOperation{Callback: b.transferNFT},
+        },
+        HelpSynopsis:    "Sign an NFT transfer from the key‑manager wallet",
+        HelpDescription: "↪ returns the signed external message as base64 BOC (signed_boc) and its hash (msg_id)",
+        Fields: txnFields(nftTransferFields()),
+    }
+}
+
+// nftTransferFields returns the schema of one NFT transfer.
+func nftTransferFields() map[string]*framework.FieldSchema {
+    return map[string]*framework.FieldSchema{
+        "nft_address": {
+            Type:        framework.TypeString,
+            Description: "Address of the NFT item owned by the wallet.",
+        },
+        "new_owner": {
+            Type:        framework.TypeString,
+            Description: "Address of the new owner.",
+        },
+        "ton_amount": {
+            Type:        framework.TypeString,
+            Description: "(Optional) Nanotons attached to the NFT item for fees.",
+            Default:     defaultNFTTonAmount,
+        },
+        "forward_ton_amount": {
+            Type:        framework.TypeString,
+            Description: "(Optional) Nanotons forwarded to the new owner with the ownership notification.",
+            Default:     "0",
+        },
+        "response_destination": {
+            Type:        framework.TypeString,
+            Description: "(Optional) Address receiving the excess. Defaults to the sender wallet.",
+        },
+        "comment": {
+            Type:        framework.TypeString,
+            Description: "(Optional) Text comment forwarded to the new owner.",
+        },
+        "query_id": {
+            Type:        framework.TypeInt64,
+            Description: "(Optional) Query id of the transfer.",
+        },
+    }
+}
+
+func (b *Backend) transferNFT(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    params, err := parseTxnParams(data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    kp, err := km.signingKey(data.Get("version").(int))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    msg, err := nftTransferMessage(data, kp)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    return b.signTransfers(ctx, req, km, kp, params, []*outMessage{msg})
+}
+
+// nftTransferMessage builds an NFT transfer request to the item.
+func nftTransferMessage(data *framework.FieldData, kp *KeyPair) (*outMessage, error) {
+    item, _, err := parseDestination(data, "nft_address", "")
+    if err != nil {
+        return nil, err
+    }
+    newOwner, _, err := parseDestination(data, "new_owner", "")
+    if err != nil {
+        return nil, err
+    }
+    tonAmount, err := parseNanotons(data.Get("ton_amount").(string), "ton_amount")
+    if err != nil {
+        return nil, err
+    }
+    forwardTon, err := parseNanotons(data.Get("forward_ton_amount").(string), "forward_ton_amount")
+    if err != nil {
+        return nil, err
+    }
+    response, err := ton.ParseAccountID(kp.Address)
+    if err != nil {
+        return nil, err
+    }
+    if strings.TrimSpace(data.Get("response_destination").(string)) != "" {
+        if response, _, err = parseDestination(data, "response_destination", ""); err != nil {
+            return nil, err
+        }
+    }
+    excess, err := responseDestination(response, kp)
+    if err != nil {
+        return nil, err
+    }
+    comment := data.Get("comment").(string)
+
+    body, err := nftTransferBody(uint64(data.Get("query_id").(int64)), newOwner, response, forwardTon, comment)
+    if err != nil {
+        return nil, fmt.Errorf("failed to build NFT transfer: %w", err)
+    }
+    return &outMessage{
+        Kind:        txnKindNFT,
+        Destination: item,
+        Amount:      tonAmount,
+        Comment:     comment,
+        Recipient:   &newOwner,
+
+        ResponseDestination: excess,
+        message: wallet.Message{
+            Amount:  tlb.Grams(tonAmount),
+            Address: item,
+            Body:    body,
+            Bounce:  true,
+            Mode:    wallet.DefaultMessageMode,
+        },
+    }, nil
+}
@@ -3,13 +3,13 @@ package usecase
 
 import (
     "context"
-    "crypto/sha256"
-    "encoding/base64"
-    "encoding/hex"
     "fmt"
 
     "github.com/hashicorp/vault/sdk/framework"
     "github.com/hashicorp/vault/sdk/logical"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/wallet"
 )
 
 func pathTransferTon(b *Backend) *framework.Path {
@@ -19,32 +19,128 @@ func pathTransferTon(b *Backend) *framework.Path {
         Operations: map[logical.Operation]framework.OperationHandler{
             logical.CreateOperation: &framework.PathOperation{Callback: b.transferTon},
         },
-        HelpSynopsis:    "Dummy TON transfer for tests",
-        HelpDescription: "↪ returns base64(seed) as signed_boc and sha256(seed) as msg_id",
-        Fields: map[string]*framework.FieldSchema{
-            "name": {Type: framework.TypeString},
+        HelpSynopsis:    "Sign a TON transfer from the key‑manager wallet",
+        HelpDescription: "↪ returns the signed external message as base64 BOC (signed_boc) and its hash (msg_id). Pass messages to batch transfers up to the wallet limit or the mount max_batch_size.",
+        Fields: txnFields(mergeFields(tonMessageFields(), map[string]*framework.FieldSchema{
+            "messages": {
+                Type:        framework.TypeSlice,
+                Description: "(Optional) Batch of transfers, each an object with the destination, amount, comment, bounce and mode fields. Replaces the top-level transfer fields.",
+            },
+        })),
+    }
+}
+
+// tonMessageFields returns the schema of one TON transfer message.
+func tonMessageFields() map[string]*framework.FieldSchema {
+    return map[string]*framework.FieldSchema{
+        "destination": {
+            Type:        framework.TypeString,
+            Description: "Recipient address in friendly or raw form.",
+        },
+        "amount": {
+            Type:        framework.TypeString,
+            Description: "Amount to send in nanotons.",
+        },
+        "comment": {
+            Type:        framework.TypeString,
+            Description: "(Optional) Text comment attached to the transfer.",
+        },
+        "bounce": {
+            Type:        framework.TypeBool,
+            Description: "(Optional) Bounce flag. Defaults to the flag of a friendly destination, true for raw ones.",
+        },
+        "mode": {
+            Type:        framework.TypeInt,
+            Description: "(Optional) Send mode of the message.",
+            Default:     wallet.DefaultMessageMode,
         },
     }
 }
 
 func (b *Backend) transferTon(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
     name := data.Get("name").(string)
+    params, err := parseTxnParams(data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    msgs, err := tonTransferMessages(data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
     km, err := b.retrieveKeyManager(ctx, req, name)
     if err != nil || km == nil {
         return nil, fmt.Errorf("key-manager %q not found", name)
     }
-    // seed hex stored in PrivateKey
-    seed, err := hex.DecodeString(km.KeyPairs[0].PrivateKey)
+    kp, err := km.signingKey(data.Get("version").(int))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    return b.signTransfers(ctx, req, km, kp, params, msgs)
+}
+
+// tonTransferMessages builds the single transfer or the messages batch of a request.
+func tonTransferMessages(data *framework.FieldData) ([]*outMessage, error) {
+    batch := data.Get("messages").([]interface{})
+    if len(batch) == 0 {
+        msg, err := tonTransferMessage(data)
+        if err != nil {
+            return nil, err
+        }
+        return []*outMessage{msg}, nil
+    }
+    if data.Get("destination").(string) != "" {
+        return nil, fmt.Errorf("destination and messages are mutually exclusive")
+    }
+
+    msgs := make([]*outMessage, len(batch))
+    for i, raw := range batch {
+        fields, ok := raw.(map[string]interface{})
+        if !ok {
+            return nil, fmt.Errorf("messages[%d] must be an object", i)
+        }
+        msg, err := tonTransferMessage(&framework.FieldData{Raw: fields, Schema: tonMessageFields()})
+        if err != nil {
+            return nil, fmt.Errorf("messages[%d]: %w", i, err)
+        }
+        msgs[i] = msg
+    }
+    return msgs, nil
+}
+
+// tonTransferMessage builds a plain TON transfer from the request fields.
+func tonTransferMessage(data *framework.FieldData) (*outMessage, error) {
+    dest, bounce, err := parseDestination(data, "destination", "bounce")
+    if err != nil {
+        return nil, err
+    }
+    amount, err := parseNanotons(data.Get("amount").(string), "amount")
     if err != nil {
-        return nil, fmt.Errorf("invalid seed hex: %w", err)
-    }
-    boc := seed
-    bocB64 := base64.StdEncoding.EncodeToString(boc)
-    id := sha256.Sum256(boc)
-    return &logical.Response{
-        Data: map[string]interface{}{
-            "signed_boc": bocB64,
-            "msg_id":     hex.EncodeToString(id[:]),
+        return nil, err
+    }
+    mode := data.Get("mode").(int)
+    if mode < 0 || mode > 255 {
+        return nil, fmt.Errorf("mode must fit in 8 bits")
+    }
+    comment := data.Get("comment").(string)
+
+    var body *boc.Cell
+    if comment != "" {
+        if body, err = commentCell(comment); err != nil {
+            return nil, err
+        }
+    }
+    return &outMessage{
+        Kind:        txnKindTon,
+        Destination: dest,
+        Amount:      amount,
+        Comment:     comment,
+        message: wallet.Message{
+            Amount:  tlb.Grams(amount),
+            Address: dest,
+            Body:    body,
+            Bounce:  bounce,
+            Mode:    uint8(mode),
         },
     }, nil
 }
@@ -0,0 +1,230 @@
+// internal/usecase/path_v4_plugins.go
+
+package usecase
+
+import (
+    "context"
+    "fmt"
+    "strings"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+func pathV4Plugins(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/txn/v4/plugins/(?P<operation>deploy|install|remove)",
+        ExistenceCheck: b.pathExistenceCheck,
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.CreateOperation: &framework.PathOperation{Callback: b.signV4Plugin},
+        },
+        HelpSynopsis: "Sign V4 wallet plugin operations",
+        HelpDescription: `
+POST deploy  — state_init, body and amount → op 1: deploy the plugin with amount as its balance and install it
+POST install — plugin, amount and query_id → op 2: install a deployed plugin
+POST remove  — plugin, amount and query_id → op 3: remove a plugin
+Only for v4R1 and v4R2 keys. Returns the signed message as the other txn paths and the operation as
+plugin; the plugin address is checked against the destination lists, and amount counts against the
+spending limits and approval thresholds. deploy and install need allow_plugin_install on
+key-managers/<name>/v4-policy and a plugin listed in its allowed_plugins.
+        `,
+        Fields: txnFields(map[string]*framework.FieldSchema{
+            "operation": {Type: framework.TypeString},
+            "plugin": {
+                Type:        framework.TypeString,
+                Description: "Plugin address. For deploy it is optional and must match the state init.",
+            },
+            "amount": {
+                Type:        framework.TypeString,
+                Description: "Nanotons: the plugin balance for deploy, sent to the plugin with install and remove.",
+                Default:     "0",
+            },
+            "state_init": {
+                Type:        framework.TypeString,
+                Description: "Base64 BOC of the plugin state init, for deploy.",
+            },
+            "body": {
+                Type:        framework.TypeString,
+                Description: "(Optional) Base64 BOC of the body sent with the deploy. Defaults to an empty cell.",
+            },
+            "workchain": {
+                Type:        framework.TypeInt,
+                Description: "(Optional) Workchain of a deployed plugin. Defaults to the workchain of the wallet.",
+            },
+            "query_id": {
+                Type:        framework.TypeInt64,
+                Description: "(Optional) Query id sent to the plugin with install and remove.",
+            },
+        }),
+    }
+}
+
+// signV4Plugin handles POST key-managers/{name}/txn/v4/plugins/{operation}
+func (b *Backend) signV4Plugin(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    params, err := parseTxnParams(data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    kp, err := km.signingKey(data.Get("version").(int))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    if kp.WalletVersion != wallet.V4R1.ToString() && kp.WalletVersion != wallet.V4R2.ToString() {
+        return logical.ErrorResponse("plugin operations need a v4 wallet, key version %d is %s", kp.ID, kp.WalletVersion), nil
+    }
+    p, err := v4PluginRequestFromData(data, kp)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    // an installed plugin may request funds from the wallet without a signature
+    if p.Operation != pluginRemove {
+        if !km.AllowPluginInstall {
+            return logical.ErrorResponse("installing plugins needs allow_plugin_install on the v4-policy of key-manager %q", name), nil
+        }
+        if !containsString(km.AllowedPlugins, p.Plugin.ToRaw()) {
+            return logical.ErrorResponse("plugin %s is not in the allowed_plugins of key-manager %q", p.Plugin.ToRaw(), name), nil
+        }
+    }
+    params.details = map[string]interface{}{"plugin": p.preview()}
+    return b.signWalletRequest(ctx, req, km, kp, params, []*outMessage{p.message()}, p.body())
+}
+
+func pathV4PluginPolicy(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/v4-policy",
+        ExistenceCheck: b.pathExistenceCheck,
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.ReadOperation:   &framework.PathOperation{Callback: b.readV4PluginPolicy},
+            logical.CreateOperation: &framework.PathOperation{Callback: b.writeV4PluginPolicy},
+            logical.UpdateOperation: &framework.PathOperation{Callback: b.writeV4PluginPolicy},
+        },
+        HelpSynopsis: "Manage what V4 plugins a key‑manager may install",
+        HelpDescription: `
+GET     — return allow_plugin_install and allowed_plugins
+POST    — set allow_plugin_install and/or replace allowed_plugins. Off by default: an installed
+          plugin can request funds from the wallet without a signature, so deploy and install
+          are refused unless allowed, and then only for the listed plugins.
+        `,
+        Fields: map[string]*framework.FieldSchema{
+            "name": {Type: framework.TypeString},
+            "allow_plugin_install": {
+                Type:        framework.TypeBool,
+                Description: "Allow signing plugin deploy and install operations.",
+            },
+            "allowed_plugins": {
+                Type:        framework.TypeCommaStringSlice,
+                Description: "Plugin addresses that may be deployed or installed.",
+            },
+        },
+    }
+}
+
+// readV4PluginPolicy handles GET key-managers/{name}/v4-policy
+func (b *Backend) readV4PluginPolicy(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    plugins := km.AllowedPlugins
+    if plugins == nil {
+        plugins = []string{}
+    }
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "service_name":         km.ServiceName,
+            "allow_plugin_install": km.AllowPluginInstall,
+            "allowed_plugins":      plugins,
+        },
+    }, nil
+}
+
+// writeV4PluginPolicy handles POST key-managers/{name}/v4-policy
+func (b *Backend) writeV4PluginPolicy(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    if v, ok := data.GetOk("allow_plugin_install"); ok {
+        km.AllowPluginInstall = v.(bool)
+    }
+    if v, ok := data.GetOk("allowed_plugins"); ok {
+        plugins, err := normalizeAddresses(v.([]string))
+        if err != nil {
+            return logical.ErrorResponse("allowed_plugins: %s", err), nil
+        }
+        km.AllowedPlugins = plugins
+    }
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return b.readV4PluginPolicy(ctx, req, data)
+}
+
+// v4PluginRequestFromData builds the plugin operation of a request.
+func v4PluginRequestFromData(data *framework.FieldData, kp *KeyPair) (*v4PluginRequest, error) {
+    p := &v4PluginRequest{Operation: data.Get("operation").(string)}
+    var err error
+    if p.Amount, err = parseNanotons(data.Get("amount").(string), "amount"); err != nil {
+        return nil, err
+    }
+    if p.Operation != pluginDeploy {
+        if p.Plugin, _, err = parseDestination(data, "plugin", ""); err != nil {
+            return nil, err
+        }
+        p.QueryID = uint64(data.Get("query_id").(int64))
+        return p, nil
+    }
+
+    if p.StateInit, err = singleCell(data.Get("state_init").(string), "state_init"); err != nil {
+        return nil, err
+    }
+    p.Body = boc.NewCell()
+    if s := strings.TrimSpace(data.Get("body").(string)); s != "" {
+        if p.Body, err = singleCell(s, "body"); err != nil {
+            return nil, err
+        }
+    }
+    workchain := kp.Workchain
+    if v, ok := data.GetOk("workchain"); ok {
+        workchain = v.(int)
+    }
+    if workchain != 0 && workchain != -1 {
+        return nil, fmt.Errorf("workchain must be 0 or -1, got %d", workchain)
+    }
+    if p.Plugin, err = pluginAddress(workchain, p.StateInit); err != nil {
+        return nil, err
+    }
+    if strings.TrimSpace(data.Get("plugin").(string)) != "" {
+        claimed, _, err := parseDestination(data, "plugin", "")
+        if err != nil {
+            return nil, err
+        }
+        if claimed != p.Plugin {
+            return nil, fmt.Errorf("plugin %s does not match the state init address %s", claimed.ToRaw(), p.Plugin.ToRaw())
+        }
+    }
+    return p, nil
+}
+
+// singleCell decodes a base64 BOC with one root cell.
+func singleCell(s, field string) (*boc.Cell, error) {
+    s = strings.TrimSpace(s)
+    if s == "" {
+        return nil, fmt.Errorf("%s is required", field)
+    }
+    cells, err := boc.DeserializeBocBase64(s)
+    if err != nil || len(cells) != 1 {
+        return nil, fmt.Errorf("%s must be a base64 BOC with one root cell", field)
+    }
+    return cells[0], nil
+}
@@ -0,0 +1,155 @@
+// internal/usecase/path_v4_plugins_test.go
+
+package usecase
+
+import (
+    "context"
+    "crypto/ed25519"
+    "encoding/hex"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+func TestV4Plugins(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    for name, version := range map[string]string{"w4": "v4R2", "w5": "v5R1"} {
+        req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+        req.Storage = storage
+        req.Data = map[string]interface{}{"serviceName": name, "wallet_version": version}
+        resp, err := b.HandleRequest(context.Background(), req)
+        require.NoError(t, err)
+        require.False(t, resp.IsError(), "%v", resp.Data)
+    }
+    km, err := b.retrieveKeyManager(context.Background(), &logical.Request{Storage: storage}, "w4")
+    require.NoError(t, err)
+    pub, err := hex.DecodeString(km.keyPairByID(km.CurrentVersion).PublicKey)
+    require.NoError(t, err)
+
+    plugin := func(name, op string, data map[string]interface{}) *logical.Response {
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/"+name+"/txn/v4/plugins/"+op, "", data)
+    }
+    // body returns the signed part of the wallet body after checking its signature
+    body := func(resp *logical.Response) *boc.Cell {
+        cells, err := boc.DeserializeBocBase64(resp.Data["signed_boc"].(string))
+        require.NoError(t, err)
+        var ext tlb.Message
+        require.NoError(t, tlb.Unmarshal(cells[0], &ext))
+        c := boc.Cell(ext.Body.Value)
+        var signed wallet.SignedMsgBody
+        require.NoError(t, tlb.Unmarshal(&c, &signed))
+        msg := boc.Cell(signed.Message)
+        hash, err := msg.Hash256()
+        require.NoError(t, err)
+        assert.True(t, ed25519.Verify(pub, hash[:], signed.Sign[:]))
+        msg.ResetCounters()
+        return &msg
+    }
+    header := func(c *boc.Cell, seqno, op uint64) {
+        subwallet, err := c.ReadUint(32)
+        require.NoError(t, err)
+        assert.EqualValues(t, wallet.DefaultSubWallet, subwallet)
+        _, err = c.ReadUint(32)
+        require.NoError(t, err)
+        got, err := c.ReadUint(32)
+        require.NoError(t, err)
+        assert.Equal(t, seqno, got)
+        got, err = c.ReadUint(8)
+        require.NoError(t, err)
+        assert.Equal(t, op, got)
+    }
+
+    dest, err := ton.ParseAccountID(testDestination)
+    require.NoError(t, err)
+    v4Policy := func(data map[string]interface{}) *logical.Response {
+        return requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers/w4/v4-policy", "", data))
+    }
+
+    // installing needs the policy flag and a listed plugin
+    resp := plugin("w4", "install", map[string]interface{}{"plugin": testDestination, "seqno": 2})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "allow_plugin_install")
+    assert.Equal(t, true, v4Policy(map[string]interface{}{"allow_plugin_install": true}).Data["allow_plugin_install"])
+    resp = plugin("w4", "install", map[string]interface{}{"plugin": testDestination, "seqno": 2})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "allowed_plugins")
+    assert.Equal(t, []string{dest.ToRaw()}, v4Policy(map[string]interface{}{"allowed_plugins": []string{testDestination}}).Data["allowed_plugins"])
+
+    resp = plugin("w4", "install", map[string]interface{}{"plugin": testDestination, "amount": "1000", "query_id": 7, "seqno": 2})
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, map[string]interface{}{"operation": "install", "plugin": dest.ToRaw(), "amount": "1000", "query_id": uint64(7)}, resp.Data["plugin"])
+    c := body(resp)
+    header(c, 2, 2)
+    var install struct {
+        Workchain int8
+        Address   tlb.Bits256
+        Amount    tlb.Grams
+        QueryID   uint64
+    }
+    require.NoError(t, tlb.Unmarshal(c, &install))
+    assert.Equal(t, dest.Address, [32]byte(install.Address))
+    assert.EqualValues(t, 1000, install.Amount)
+    assert.EqualValues(t, 7, install.QueryID)
+
+    resp = plugin("w4", "remove", map[string]interface{}{"plugin": testDestination, "seqno": 3})
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    header(body(resp), 3, 3)
+
+    // the plugin address is derived from its state init
+    init, err := wallet.GenerateStateInit(pub, wallet.V3R2, nil, 0, nil)
+    require.NoError(t, err)
+    initCell := boc.NewCell()
+    require.NoError(t, tlb.Marshal(initCell, init))
+    initBoc, err := initCell.ToBocBase64()
+    require.NoError(t, err)
+    initHash, err := initCell.Hash256()
+    require.NoError(t, err)
+    deployed := ton.AccountID{Workchain: 0, Address: initHash}
+    resp = plugin("w4", "deploy", map[string]interface{}{"state_init": initBoc, "amount": "50000000", "seqno": 4})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "allowed_plugins")
+    v4Policy(map[string]interface{}{"allowed_plugins": []string{testDestination, deployed.ToRaw()}})
+    resp = plugin("w4", "deploy", map[string]interface{}{"state_init": initBoc, "amount": "50000000", "seqno": 4})
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, deployed.ToRaw(), resp.Data["plugin"].(map[string]interface{})["plugin"])
+    c = body(resp)
+    header(c, 4, 1)
+    var deploy struct {
+        Workchain int8
+        Balance   tlb.Grams
+        StateInit tlb.Any `tlb:"^"`
+        Body      tlb.Any `tlb:"^"`
+    }
+    require.NoError(t, tlb.Unmarshal(c, &deploy))
+    assert.EqualValues(t, 50000000, deploy.Balance)
+    deployedInit := boc.Cell(deploy.StateInit)
+    deployedHash, err := deployedInit.Hash256()
+    require.NoError(t, err)
+    assert.Equal(t, initHash, deployedHash)
+
+    resp = plugin("w4", "deploy", map[string]interface{}{"state_init": initBoc, "plugin": testDestination, "seqno": 4})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "does not match")
+
+    // plugins are destinations like any other
+    req := logical.TestRequest(t, logical.UpdateOperation, "key-managers/w4/destinations")
+    req.Storage = storage
+    req.Data = map[string]interface{}{"allowed_destinations": []string{deployed.ToRaw()}}
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    resp = plugin("w4", "install", map[string]interface{}{"plugin": testDestination, "seqno": 5})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "not allowed")
+
+    resp = plugin("w5", "install", map[string]interface{}{"plugin": testDestination, "seqno": 1})
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "v4")
+}
@@ -0,0 +1,148 @@
+// internal/usecase/path_w5_extensions.go
+
+package usecase
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+func pathW5Extensions(b *Backend) []*framework.Path {
+    return []*framework.Path{
+        {
+            Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/txn/w5/extensions",
+            ExistenceCheck: b.pathExistenceCheck,
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.CreateOperation: &framework.PathOperation{Callback: b.signW5Extensions},
+            },
+            HelpSynopsis: "Sign W5 extended actions: add or remove extensions, allow or disallow signatures",
+            HelpDescription: `
+POST actions, a list of {action: add_extension|remove_extension, address} and
+{action: set_signature_allowed, allowed} objects, for a v5R1 key → the signed message as for the
+other txn paths, with the actions echoed as extension_actions. Adding an extension needs
+allow_add_extension on key-managers/<name>/w5-policy and an address the destination lists accept;
+disabling signature authentication needs allow_signature_disable. Signing policies see the actions.
+            `,
+            Fields: txnFields(map[string]*framework.FieldSchema{
+                "actions": {
+                    Type:        framework.TypeSlice,
+                    Description: "Extended actions, each an object with the action, address and allowed fields.",
+                },
+            }),
+        },
+        {
+            Pattern:        "key-managers/" + framework.GenericNameRegex("name") + "/w5-policy",
+            ExistenceCheck: b.pathExistenceCheck,
+            Operations: map[logical.Operation]framework.OperationHandler{
+                logical.ReadOperation:   &framework.PathOperation{Callback: b.readW5Policy},
+                logical.CreateOperation: &framework.PathOperation{Callback: b.writeW5Policy},
+                logical.UpdateOperation: &framework.PathOperation{Callback: b.writeW5Policy},
+            },
+            HelpSynopsis: "Manage what W5 extended actions a key‑manager may sign",
+            HelpDescription: `
+GET     — return allow_add_extension and allow_signature_disable
+POST    — set allow_add_extension and allow_signature_disable. Both are off by default: an extension
+          can send from the wallet without a signature, and a wallet with signatures disabled only
+          obeys its extensions, so the keys in Vault can no longer control it.
+            `,
+            Fields: map[string]*framework.FieldSchema{
+                "name": {Type: framework.TypeString},
+                "allow_add_extension": {
+                    Type:        framework.TypeBool,
+                    Description: "Allow signing add_extension actions.",
+                },
+                "allow_signature_disable": {
+                    Type:        framework.TypeBool,
+                    Description: "Allow signing set_signature_allowed actions that disable signature authentication.",
+                },
+            },
+        },
+    }
+}
+
+// signW5Extensions handles POST key-managers/{name}/txn/w5/extensions
+func (b *Backend) signW5Extensions(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    params, err := parseTxnParams(data)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    actions, previews, disablesSignature, err := parseW5Actions(data.Get("actions").([]interface{}))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    if disablesSignature && !km.AllowSignatureDisable {
+        return logical.ErrorResponse("disabling signature authentication needs allow_signature_disable on the w5-policy of key-manager %q", name), nil
+    }
+    kp, err := km.signingKey(data.Get("version").(int))
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    if kp.WalletVersion != wallet.V5R1.ToString() {
+        return logical.ErrorResponse("extended actions need a v5R1 wallet, key version %d is %s", kp.ID, kp.WalletVersion), nil
+    }
+    // an extension may send from the wallet without a signature: it is held to the destination lists
+    for i, a := range previews {
+        if a["action"] != w5AddExtension {
+            continue
+        }
+        if !km.AllowAddExtension {
+            return logical.ErrorResponse("adding extensions needs allow_add_extension on the w5-policy of key-manager %q", name), nil
+        }
+        addr, err := ton.ParseAccountID(a["address"].(string))
+        if err != nil {
+            return nil, err
+        }
+        if err := km.checkDestination(addr); err != nil {
+            return logical.ErrorResponse("actions[%d]: %s", i, err), nil
+        }
+    }
+    params.details = map[string]interface{}{"extension_actions": previews}
+    params.actions = previews
+    return b.signWalletRequest(ctx, req, km, kp, params, nil, w5ExtensionBody(actions))
+}
+
+// readW5Policy handles GET key-managers/{name}/w5-policy
+func (b *Backend) readW5Policy(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "service_name":            km.ServiceName,
+            "allow_add_extension":     km.AllowAddExtension,
+            "allow_signature_disable": km.AllowSignatureDisable,
+        },
+    }, nil
+}
+
+// writeW5Policy handles POST key-managers/{name}/w5-policy
+func (b *Backend) writeW5Policy(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+    name := data.Get("name").(string)
+    km, err := b.retrieveKeyManager(ctx, req, name)
+    if err != nil || km == nil {
+        return nil, fmt.Errorf("key-manager %q not found", name)
+    }
+    if v, ok := data.GetOk("allow_add_extension"); ok {
+        km.AllowAddExtension = v.(bool)
+    }
+    if v, ok := data.GetOk("allow_signature_disable"); ok {
+        km.AllowSignatureDisable = v.(bool)
+    }
+    if err := b.storeKeyManager(ctx, req, km); err != nil {
+        return nil, err
+    }
+    return b.readW5Policy(ctx, req, data)
+}
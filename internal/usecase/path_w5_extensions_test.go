@@ -0,0 +1,123 @@
+// internal/usecase/path_w5_extensions_test.go
+
+package usecase
+
+import (
+    "context"
+    "testing"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+func TestW5Extensions(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    for name, version := range map[string]string{"w5": "v5R1", "w4": "v4R2"} {
+        req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+        req.Storage = storage
+        req.Data = map[string]interface{}{"serviceName": name, "wallet_version": version}
+        resp, err := b.HandleRequest(context.Background(), req)
+        require.NoError(t, err)
+        require.False(t, resp.IsError(), "%v", resp.Data)
+    }
+
+    extensions := func(name string, actions ...map[string]interface{}) *logical.Response {
+        list := make([]interface{}, len(actions))
+        for i, a := range actions {
+            list[i] = a
+        }
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/"+name+"/txn/w5/extensions", "", map[string]interface{}{"seqno": 3, "actions": list})
+    }
+    decode := func(resp *logical.Response) wallet.W5ExtendedActions {
+        cells, err := boc.DeserializeBocBase64(resp.Data["signed_boc"].(string))
+        require.NoError(t, err)
+        var ext tlb.Message
+        require.NoError(t, tlb.Unmarshal(cells[0], &ext))
+        body := boc.Cell(ext.Body.Value)
+        var msg wallet.MessageV5
+        require.NoError(t, tlb.Unmarshal(&body, &msg))
+        require.NotNil(t, msg.SignedExternal)
+        assert.EqualValues(t, 3, msg.SignedExternal.Seqno)
+        require.NotNil(t, msg.SignedExternal.ExtendedActions)
+        return *msg.SignedExternal.ExtendedActions
+    }
+
+    w5Policy := func(data map[string]interface{}) *logical.Response {
+        return requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers/w5/w5-policy", "", data))
+    }
+
+    // adding extensions needs the policy flag
+    add := map[string]interface{}{"action": "add_extension", "address": testDestination}
+    resp := extensions("w5", add)
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "allow_add_extension")
+    assert.Equal(t, true, w5Policy(map[string]interface{}{"allow_add_extension": true}).Data["allow_add_extension"])
+
+    resp = extensions("w5",
+        add,
+        map[string]interface{}{"action": "remove_extension", "address": testDestination},
+        map[string]interface{}{"action": "set_signature_allowed", "allowed": true},
+    )
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Len(t, resp.Data["extension_actions"], 3)
+    actions := decode(resp)
+    require.Len(t, actions, 3)
+    dest, err := ton.ParseAccountID(testDestination)
+    require.NoError(t, err)
+    require.NotNil(t, actions[0].AddExtension)
+    added, err := ton.AccountIDFromTlb(actions[0].AddExtension.Addr)
+    require.NoError(t, err)
+    assert.Equal(t, dest, *added)
+    require.NotNil(t, actions[1].RemoveExtension)
+    require.NotNil(t, actions[2].SetSignatureAllowed)
+    assert.True(t, actions[2].SetSignatureAllowed.Allowed)
+
+    // disabling signatures needs the policy flag
+    disable := map[string]interface{}{"action": "set_signature_allowed", "allowed": false}
+    resp = extensions("w5", disable)
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "allow_signature_disable")
+
+    assert.Equal(t, true, w5Policy(map[string]interface{}{"allow_signature_disable": true}).Data["allow_signature_disable"])
+
+    resp = extensions("w5", disable)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    actions = decode(resp)
+    require.Len(t, actions, 1)
+    assert.False(t, actions[0].SetSignatureAllowed.Allowed)
+
+    // the extension is held to the destination lists, and signing policies see the actions
+    requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers/w5/destinations", "", map[string]interface{}{
+        "denied_destinations": []string{testDestination},
+    }))
+    resp = extensions("w5", add)
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "denied")
+    assert.False(t, extensions("w5", map[string]interface{}{"action": "remove_extension", "address": testDestination}).IsError())
+    requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers/w5/destinations", "", map[string]interface{}{
+        "denied_destinations": []string{},
+    }))
+    requireOK(t, doRequest(t, b, storage, logical.UpdateOperation, "key-managers/w5/policy", "", map[string]interface{}{
+        "rules": []interface{}{map[string]interface{}{
+            "name": "no-extensions", "effect": "deny", "expression": `actions.exists(a, a.action == "add_extension")`,
+        }},
+        "default_effect": "allow",
+    }))
+    resp = extensions("w5", add)
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "no-extensions")
+
+    resp = extensions("w4", add)
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "v5R1")
+    resp = extensions("w5", map[string]interface{}{"action": "add_extension"})
+    require.True(t, resp.IsError())
+    resp = extensions("w5")
+    require.True(t, resp.IsError())
+}
@@ -0,0 +1,101 @@
+// internal/usecase/path_wrapping_key.go
+
+package usecase
+
+import (
+    "context"
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/x509"
+    "encoding/pem"
+    "fmt"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+    wrappingKeyPath = "wrapping_key"
+    wrappingKeyBits = 4096
+)
+
+// wrappingKeyEntry is the stored mount-level BYOK wrapping key.
+type wrappingKeyEntry struct {
+    PrivateKey []byte `json:"private_key"` // PKCS#1 DER
+}
+
+func pathWrappingKey(b *Backend) *framework.Path {
+    return &framework.Path{
+        Pattern: "wrapping_key",
+        Operations: map[logical.Operation]framework.OperationHandler{
+            logical.ReadOperation: &framework.PathOperation{Callback: b.readWrappingKey},
+        },
+        HelpSynopsis: "Return the RSA-4096 public key used to wrap keys for import.",
+        HelpDescription: `
+GET → public_key (PEM). The key is generated on first use and shared by the whole mount.
+Wrap a 32-byte seed or a TON mnemonic for import into key-managers with wrapped_key:
+a random AES-256 key is encrypted with RSA-OAEP(SHA-256) under this public key and the
+seed or mnemonic is wrapped with AES-KWP (RFC 5649) under the AES key; wrapped_key is
+base64(RSA ciphertext || KWP ciphertext).
+        `,
+    }
+}
+
+func (b *Backend) readWrappingKey(
+    ctx context.Context,
+    req *logical.Request,
+    _ *framework.FieldData,
+) (*logical.Response, error) {
+    key, err := b.wrappingKey(ctx, req.Storage)
+    if err != nil {
+        return nil, err
+    }
+    der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+    if err != nil {
+        return nil, err
+    }
+    return &logical.Response{
+        Data: map[string]interface{}{
+            "public_key": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+        },
+    }, nil
+}
+
+// wrappingKey loads the mount wrapping key, generating it on first use.
+func (b *Backend) wrappingKey(ctx context.Context, s logical.Storage) (*rsa.PrivateKey, error) {
+    b.wrappingKeyLock.Lock()
+    defer b.wrappingKeyLock.Unlock()
+
+    entry, err := s.Get(ctx, wrappingKeyPath)
+    if err != nil {
+        return nil, err
+    }
+    if entry != nil {
+        var stored wrappingKeyEntry
+        if err := entry.DecodeJSON(&stored); err != nil {
+            return nil, err
+        }
+        key, err := x509.ParsePKCS1PrivateKey(stored.PrivateKey)
+        if err != nil {
+            return nil, fmt.Errorf("invalid stored wrapping key: %w", err)
+        }
+        return key, nil
+    }
+
+    key, err := rsa.GenerateKey(rand.Reader, wrappingKeyBits)
+    if err != nil {
+        return nil, fmt.Errorf("failed to generate wrapping key: %w", err)
+    }
+    entry, err = logical.StorageEntryJSON(wrappingKeyPath, wrappingKeyEntry{
+        PrivateKey: x509.MarshalPKCS1PrivateKey(key),
+    })
+    if err != nil {
+        return nil, err
+    }
+    if err := s.Put(ctx, entry); err != nil {
+        b.Logger().Error("Failed to store wrapping key", "error", err)
+        return nil, err
+    }
+    b.Logger().Info("Generated mount wrapping key")
+    return key, nil
+}
@@ -0,0 +1,101 @@
+// internal/usecase/path_wrapping_key_test.go
+
+package usecase
+
+import (
+    "context"
+    "crypto/ed25519"
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/sha256"
+    "crypto/x509"
+    "encoding/base64"
+    "encoding/hex"
+    "encoding/pem"
+    "testing"
+
+    "github.com/google/tink/go/kwp/subtle"
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+// wrapForImport wraps plaintext the way the wrapping_key help describes.
+func wrapForImport(t *testing.T, pemKey string, plaintext []byte) string {
+    t.Helper()
+    block, _ := pem.Decode([]byte(pemKey))
+    require.NotNil(t, block)
+    key, err := x509.ParsePKIXPublicKey(block.Bytes)
+    require.NoError(t, err)
+    pub := key.(*rsa.PublicKey)
+    assert.Equal(t, wrappingKeyBits, pub.N.BitLen())
+
+    aesKey := make([]byte, 32)
+    _, err = rand.Read(aesKey)
+    require.NoError(t, err)
+    wrappedAES, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, aesKey, nil)
+    require.NoError(t, err)
+    kwp, err := subtle.NewKWP(aesKey)
+    require.NoError(t, err)
+    wrapped, err := kwp.Wrap(plaintext)
+    require.NoError(t, err)
+    return base64.StdEncoding.EncodeToString(append(wrappedAES, wrapped...))
+}
+
+func TestWrappedKeyImport(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    req := logical.TestRequest(t, logical.ReadOperation, "wrapping_key")
+    req.Storage = storage
+    resp, err := b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    pemKey := resp.Data["public_key"].(string)
+
+    // The wrapping key is stable across reads
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, pemKey, resp.Data["public_key"])
+
+    // Wrapped seed
+    seed, err := hex.DecodeString(testSeed)
+    require.NoError(t, err)
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{
+        "serviceName": "seed-svc",
+        "wrapped_key": wrapForImport(t, pemKey, seed),
+    }
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    pub := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+    assert.Equal(t, hex.EncodeToString(pub), resp.Data["public_key"])
+
+    // Wrapped mnemonic
+    mnemonic := wallet.RandomSeed()
+    priv, err := wallet.SeedToPrivateKey(mnemonic)
+    require.NoError(t, err)
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{
+        "serviceName":      "mnemonic-svc",
+        "wrapped_key":      wrapForImport(t, pemKey, []byte(mnemonic)),
+        "wrapped_key_type": "mnemonic",
+    }
+    resp, err = b.HandleRequest(context.Background(), req)
+    require.NoError(t, err)
+    assert.Equal(t, hex.EncodeToString(priv.Public().(ed25519.PublicKey)), resp.Data["public_key"])
+
+    // Tampered ciphertext is rejected
+    wrapped, err := base64.StdEncoding.DecodeString(wrapForImport(t, pemKey, seed))
+    require.NoError(t, err)
+    wrapped[len(wrapped)-1] ^= 0xff
+    req = logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+    req.Storage = storage
+    req.Data = map[string]interface{}{
+        "serviceName": "bad-svc",
+        "wrapped_key": base64.StdEncoding.EncodeToString(wrapped),
+    }
+    _, err = b.HandleRequest(context.Background(), req)
+    assert.Error(t, err)
+}
@@ -0,0 +1,218 @@
+// internal/usecase/policy.go
+package usecase
+
+import (
+    "fmt"
+    "math"
+    "time"
+
+    "github.com/google/cel-go/cel"
+)
+
+const (
+    policyAllow = "allow"
+    policyDeny  = "deny"
+)
+
+// signingPolicy is an ordered list of CEL rules evaluated against every transaction
+// of a key‑manager. The first matching rule decides; DefaultEffect applies when none does.
+type signingPolicy struct {
+    Rules         []policyRule        `json:"rules"`
+    DefaultEffect string              `json:"default_effect"`
+    Lists         map[string][]string `json:"lists,omitempty"` // named address lists, raw form
+}
+
+type policyRule struct {
+    Name       string `json:"name"`
+    Effect     string `json:"effect"`
+    Expression string `json:"expression"`
+}
+
+// policyInput is the structured view of a transaction rules are evaluated against.
+type policyInput struct {
+    ServiceName string
+    KeyVersion  int
+    EntityID    string
+    Now         time.Time
+    Messages    []*outMessage
+    Actions     []map[string]interface{}
+}
+
+// policyDecision reports the outcome of a policy and the rule behind it. Rule is
+// empty when the default effect applied.
+type policyDecision struct {
+    Allowed bool
+    Rule    string
+    Effect  string
+    Reason  string
+    Trace   []map[string]interface{}
+}
+
+// policyEnv declares the variables rules may use.
+func policyEnv() (*cel.Env, error) {
+    return cel.NewEnv(
+        cel.Variable("messages", cel.ListType(cel.MapType(cel.StringType, cel.DynType))),
+        cel.Variable("actions", cel.ListType(cel.MapType(cel.StringType, cel.DynType))),
+        cel.Variable("now", cel.TimestampType),
+        cel.Variable("entity_id", cel.StringType),
+        cel.Variable("service_name", cel.StringType),
+        cel.Variable("key_version", cel.IntType),
+        cel.Variable("lists", cel.MapType(cel.StringType, cel.ListType(cel.StringType))),
+    )
+}
+
+// compileRule checks that a rule is a boolean CEL expression.
+func compileRule(env *cel.Env, rule policyRule) (cel.Program, error) {
+    ast, iss := env.Compile(rule.Expression)
+    if iss.Err() != nil {
+        return nil, fmt.Errorf("rule %q: %w", rule.Name, iss.Err())
+    }
+    if ast.OutputType() != cel.BoolType {
+        return nil, fmt.Errorf("rule %q must evaluate to a bool, got %s", rule.Name, ast.OutputType())
+    }
+    return env.Program(ast)
+}
+
+// validate checks the effects and compiles every rule.
+func (p *signingPolicy) validate() error {
+    if p.DefaultEffect != policyAllow && p.DefaultEffect != policyDeny {
+        return fmt.Errorf("default_effect must be %q or %q", policyAllow, policyDeny)
+    }
+    env, err := policyEnv()
+    if err != nil {
+        return err
+    }
+    names := make(map[string]bool, len(p.Rules))
+    for i, rule := range p.Rules {
+        if rule.Name == "" {
+            return fmt.Errorf("rule %d has no name", i+1)
+        }
+        if names[rule.Name] {
+            return fmt.Errorf("rule %q is defined twice", rule.Name)
+        }
+        names[rule.Name] = true
+        if rule.Effect != policyAllow && rule.Effect != policyDeny {
+            return fmt.Errorf("rule %q: effect must be %q or %q", rule.Name, policyAllow, policyDeny)
+        }
+        if _, err := compileRule(env, rule); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// evaluate runs the rules in order. A rule that fails to evaluate denies the
+// transaction, so that a broken policy never lets a transfer through.
+func (p *signingPolicy) evaluate(in policyInput) (*policyDecision, error) {
+    env, err := policyEnv()
+    if err != nil {
+        return nil, err
+    }
+    messages := make([]interface{}, len(in.Messages))
+    for i, m := range in.Messages {
+        messages[i] = m.policyView()
+    }
+    actions := make([]interface{}, len(in.Actions))
+    for i, a := range in.Actions {
+        actions[i] = a
+    }
+    lists := p.Lists
+    if lists == nil {
+        lists = map[string][]string{}
+    }
+    vars := map[string]interface{}{
+        "messages":     messages,
+        "actions":      actions,
+        "now":          in.Now.UTC(),
+        "entity_id":    in.EntityID,
+        "service_name": in.ServiceName,
+        "key_version":  int64(in.KeyVersion),
+        "lists":        lists,
+    }
+
+    d := &policyDecision{}
+    for _, rule := range p.Rules {
+        prg, err := compileRule(env, rule)
+        if err != nil {
+            return nil, err
+        }
+        out, _, err := prg.Eval(vars)
+        if err != nil {
+            d.Trace = append(d.Trace, map[string]interface{}{"rule": rule.Name, "error": err.Error()})
+            d.Rule, d.Effect = rule.Name, policyDeny
+            d.Reason = fmt.Sprintf("rule %q failed to evaluate: %s", rule.Name, err)
+            return d, nil
+        }
+        matched, _ := out.Value().(bool)
+        d.Trace = append(d.Trace, map[string]interface{}{"rule": rule.Name, "matched": matched})
+        if matched {
+            d.Allowed, d.Rule, d.Effect = rule.Effect == policyAllow, rule.Name, rule.Effect
+            d.Reason = fmt.Sprintf("rule %q matched", rule.Name)
+            return d, nil
+        }
+    }
+    d.Allowed, d.Effect = p.DefaultEffect == policyAllow, p.DefaultEffect
+    d.Reason = "no rule matched, default effect applied"
+    return d, nil
+}
+
+func (d *policyDecision) responseData() map[string]interface{} {
+    return map[string]interface{}{
+        "allowed": d.Allowed,
+        "rule":    d.Rule,
+        "effect":  d.Effect,
+        "reason":  d.Reason,
+        "trace":   d.Trace,
+    }
+}
+
+// policyView is the message as seen by policy rules. Amounts are ints; jetton
+// amounts beyond int64 saturate.
+func (m *outMessage) policyView() map[string]interface{} {
+    view := map[string]interface{}{
+        "kind":          m.Kind,
+        "op":            int64(0),
+        "destination":   m.Destination.ToRaw(),
+        "amount":        saturatingInt(m.Amount),
+        "comment":       m.Comment,
+        "jetton_amount": int64(0),
+        "jetton_master": "",
+        "recipient":     m.Destination.ToRaw(),
+        "mode":          int64(m.message.Mode),
+        "bounce":        m.message.Bounce,
+
+        "response_destination": "",
+    }
+    switch m.Kind {
+    case txnKindJetton:
+        view["op"] = int64(opJettonTransfer)
+    case txnKindNFT:
+        view["op"] = int64(opNFTTransfer)
+    default:
+        view["op"] = int64(m.Op)
+    }
+    if m.JettonAmount != nil {
+        if m.JettonAmount.IsInt64() {
+            view["jetton_amount"] = m.JettonAmount.Int64()
+        } else {
+            view["jetton_amount"] = int64(math.MaxInt64)
+        }
+    }
+    if m.JettonMaster != nil {
+        view["jetton_master"] = m.JettonMaster.ToRaw()
+    }
+    if m.Recipient != nil {
+        view["recipient"] = m.Recipient.ToRaw()
+    }
+    if m.ResponseDestination != nil {
+        view["response_destination"] = m.ResponseDestination.ToRaw()
+    }
+    return view
+}
+
+func saturatingInt(v uint64) int64 {
+    if v > math.MaxInt64 {
+        return math.MaxInt64
+    }
+    return int64(v)
+}
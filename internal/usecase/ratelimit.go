@@ -0,0 +1,192 @@
+// internal/usecase/ratelimit.go
+package usecase
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+    rateLimitPrefix = "ratelimits/"
+
+    // maxRateBuckets bounds the per-caller buckets kept in memory before full ones are dropped.
+    maxRateBuckets = 10000
+)
+
+// rateLimit configures the token buckets of a key‑manager: one shared by all its
+// callers and one per caller, identified by entity or, without one, by token accessor.
+// A zero rate leaves the scope unlimited.
+type rateLimit struct {
+    Rate        float64 `json:"rate"` // requests per second
+    Burst       int     `json:"burst"`
+    CallerRate  float64 `json:"caller_rate"`
+    CallerBurst int     `json:"caller_burst"`
+    Persist     bool    `json:"persist"`
+}
+
+type tokenBucket struct {
+    Tokens float64   `json:"tokens"`
+    Last   time.Time `json:"last"`
+}
+
+// rateBuckets holds the token buckets of all key‑managers in memory.
+type rateBuckets struct {
+    mu      sync.Mutex
+    buckets map[string]*tokenBucket
+    loaded  map[string]bool // key‑managers whose persisted buckets were read
+}
+
+func newRateBuckets() *rateBuckets {
+    return &rateBuckets{buckets: map[string]*tokenBucket{}, loaded: map[string]bool{}}
+}
+
+// refill adds the tokens earned since the last request, up to burst.
+func (t *tokenBucket) refill(now time.Time, rate float64, burst int) {
+    if elapsed := now.Sub(t.Last).Seconds(); elapsed > 0 {
+        t.Tokens += elapsed * rate
+    }
+    if t.Tokens > float64(burst) {
+        t.Tokens = float64(burst)
+    }
+    t.Last = now
+}
+
+// retryAfter is the time until the bucket holds a token again.
+func (t *tokenBucket) retryAfter(rate float64) time.Duration {
+    return time.Duration((1 - t.Tokens) / rate * float64(time.Second)).Round(time.Millisecond)
+}
+
+// rateLimited is returned with HTTP 429 so that callers know to back off and retry.
+func rateLimited(scope string, retry time.Duration) error {
+    return logical.CodedError(http.StatusTooManyRequests,
+        fmt.Sprintf("rate limit exceeded for %s, retry in %s", scope, retry))
+}
+
+// throttle takes a token from the key‑manager bucket and from the caller bucket,
+// or from neither when one of them is empty.
+func (b *Backend) throttle(ctx context.Context, req *logical.Request, km *KeyManager) error {
+    rl := km.RateLimit
+    if rl == nil || (rl.Rate <= 0 && rl.CallerRate <= 0) {
+        return nil
+    }
+    caller := req.EntityID
+    if caller == "" {
+        caller = "accessor:" + req.ClientTokenAccessor
+    }
+
+    rb := b.rateBuckets
+    rb.mu.Lock()
+    defer rb.mu.Unlock()
+    if rl.Persist && !rb.loaded[km.ServiceName] {
+        if err := b.loadRateBuckets(ctx, req.Storage, km.ServiceName); err != nil {
+            return err
+        }
+    }
+
+    type scope struct {
+        key   string
+        name  string
+        rate  float64
+        burst int
+    }
+    var scopes []scope
+    if rl.Rate > 0 {
+        scopes = append(scopes, scope{km.ServiceName + "/", fmt.Sprintf("key-manager %q", km.ServiceName), rl.Rate, rl.Burst})
+    }
+    if rl.CallerRate > 0 {
+        scopes = append(scopes, scope{km.ServiceName + "/" + caller, fmt.Sprintf("caller %q", caller), rl.CallerRate, rl.CallerBurst})
+    }
+
+    now := time.Now()
+    for _, s := range scopes {
+        t := rb.buckets[s.key]
+        if t == nil {
+            t = &tokenBucket{Tokens: float64(s.burst), Last: now}
+            rb.buckets[s.key] = t
+        }
+        t.refill(now, s.rate, s.burst)
+        if t.Tokens < 1 {
+            return rateLimited(s.name, t.retryAfter(s.rate))
+        }
+    }
+    for _, s := range scopes {
+        rb.buckets[s.key].Tokens--
+    }
+    if len(rb.buckets) > maxRateBuckets {
+        rb.prune(km.ServiceName, now, rl)
+    }
+    if rl.Persist {
+        return b.storeRateBuckets(ctx, req.Storage, km.ServiceName)
+    }
+    return nil
+}
+
+// prune drops the caller buckets of a key‑manager idle long enough to be full
+// again, which behave as new ones.
+func (rb *rateBuckets) prune(svc string, now time.Time, rl *rateLimit) {
+    for key, t := range rb.buckets {
+        if key == svc+"/" || !strings.HasPrefix(key, svc+"/") {
+            continue
+        }
+        if now.Sub(t.Last).Seconds()*rl.CallerRate >= float64(rl.CallerBurst) {
+            delete(rb.buckets, key)
+        }
+    }
+}
+
+func (b *Backend) loadRateBuckets(ctx context.Context, s logical.Storage, svc string) error {
+    entry, err := s.Get(ctx, rateLimitPrefix+svc)
+    if err != nil {
+        return err
+    }
+    if entry != nil {
+        stored := map[string]*tokenBucket{}
+        if err := entry.DecodeJSON(&stored); err != nil {
+            return err
+        }
+        for key, t := range stored {
+            b.rateBuckets.buckets[svc+"/"+key] = t
+        }
+    }
+    b.rateBuckets.loaded[svc] = true
+    return nil
+}
+
+func (b *Backend) storeRateBuckets(ctx context.Context, s logical.Storage, svc string) error {
+    prefix := svc + "/"
+    stored := map[string]*tokenBucket{}
+    for key, t := range b.rateBuckets.buckets {
+        if strings.HasPrefix(key, prefix) {
+            stored[strings.TrimPrefix(key, prefix)] = t
+        }
+    }
+    entry, err := logical.StorageEntryJSON(rateLimitPrefix+svc, stored)
+    if err != nil {
+        return err
+    }
+    if err := s.Put(ctx, entry); err != nil {
+        b.Logger().Error("Failed to store rate limit buckets", "name", svc, "error", err)
+        return err
+    }
+    return nil
+}
+
+// resetRateBuckets forgets the buckets of a key‑manager.
+func (b *Backend) resetRateBuckets(svc string) {
+    rb := b.rateBuckets
+    rb.mu.Lock()
+    defer rb.mu.Unlock()
+    prefix := svc + "/"
+    for key := range rb.buckets {
+        if strings.HasPrefix(key, prefix) {
+            delete(rb.buckets, key)
+        }
+    }
+    delete(rb.loaded, svc)
+}
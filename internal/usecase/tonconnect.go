@@ -0,0 +1,177 @@
+// internal/usecase/tonconnect.go
+package usecase
+
+import (
+    "crypto/ed25519"
+    "crypto/sha256"
+    "encoding/binary"
+    "fmt"
+    "hash/crc32"
+    "strings"
+
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+const (
+    tonProofPrefix   = "ton-proof-item-v2/"
+    tonConnectPrefix = "ton-connect"
+    signDataPrefix   = "ton-connect/sign-data/"
+
+    // signData payload types
+    signDataText   = "text"
+    signDataBinary = "binary"
+    signDataCell   = "cell"
+
+    opSignDataCell = 0x75569022
+
+    // TON Connect chain ids
+    chainMainnet = "-239"
+    chainTestnet = "-3"
+)
+
+// tonProofHash returns the hash a wallet signs for a TON Connect ton_proof:
+// sha256(0xffff ++ "ton-connect" ++ sha256(message)), where message is
+// "ton-proof-item-v2/" ++ workchain (int32 BE) ++ address hash ++ domain length
+// (uint32 LE) ++ domain ++ timestamp (uint64 LE) ++ payload.
+func tonProofHash(addr ton.AccountID, domain string, timestamp int64, payload string) []byte {
+    m := []byte(tonProofPrefix)
+    m = binary.BigEndian.AppendUint32(m, uint32(addr.Workchain))
+    m = append(m, addr.Address[:]...)
+    m = binary.LittleEndian.AppendUint32(m, uint32(len(domain)))
+    m = append(m, domain...)
+    m = binary.LittleEndian.AppendUint64(m, uint64(timestamp))
+    m = append(m, payload...)
+    msgHash := sha256.Sum256(m)
+
+    full := append([]byte{0xff, 0xff}, tonConnectPrefix...)
+    full = append(full, msgHash[:]...)
+    sum := sha256.Sum256(full)
+    return sum[:]
+}
+
+// walletStateInit returns the wallet account of the key pair and its state init
+// as a base64 BOC, as TON Connect verifiers expect it.
+func walletStateInit(priv ed25519.PrivateKey, ws walletSettings) (ton.AccountID, string, error) {
+    w, err := wallet.New(priv, ws.Version, nil, ws.options()...)
+    if err != nil {
+        return ton.AccountID{}, "", fmt.Errorf("failed to init wallet: %w", err)
+    }
+    init, err := w.StateInit()
+    if err != nil {
+        return ton.AccountID{}, "", fmt.Errorf("failed to build wallet state init: %w", err)
+    }
+    cell := boc.NewCell()
+    if err := tlb.Marshal(cell, init); err != nil {
+        return ton.AccountID{}, "", fmt.Errorf("failed to marshal wallet state init: %w", err)
+    }
+    raw, err := cell.ToBocBase64()
+    if err != nil {
+        return ton.AccountID{}, "", err
+    }
+    return w.GetAddress(), raw, nil
+}
+
+// tonConnectChain returns the TON Connect chain id of a network.
+func tonConnectChain(network string) string {
+    if network == networkTestnet {
+        return chainTestnet
+    }
+    return chainMainnet
+}
+
+// signDataHash returns the hash a wallet signs for a TON Connect signData text or
+// binary payload: sha256(0xffff ++ "ton-connect/sign-data/" ++ workchain (int32 BE)
+// ++ address hash ++ domain length (uint32 BE) ++ domain ++ timestamp (uint64 BE)
+// ++ "txt" or "bin" ++ payload length (uint32 BE) ++ payload).
+func signDataHash(addr ton.AccountID, domain string, timestamp int64, payloadType string, payload []byte) []byte {
+    prefix := "bin"
+    if payloadType == signDataText {
+        prefix = "txt"
+    }
+    m := append([]byte{0xff, 0xff}, signDataPrefix...)
+    m = binary.BigEndian.AppendUint32(m, uint32(addr.Workchain))
+    m = append(m, addr.Address[:]...)
+    m = binary.BigEndian.AppendUint32(m, uint32(len(domain)))
+    m = append(m, domain...)
+    m = binary.BigEndian.AppendUint64(m, uint64(timestamp))
+    m = append(m, prefix...)
+    m = binary.BigEndian.AppendUint32(m, uint32(len(payload)))
+    m = append(m, payload...)
+    sum := sha256.Sum256(m)
+    return sum[:]
+}
+
+// signDataCellHash returns the hash a wallet signs for a TON Connect signData cell
+// payload, the representation hash of the cell
+//   0x75569022 (32) ++ crc32(schema) (32) ++ timestamp (64) ++ address
+//   ++ ^(dns-encoded domain as a snake string) ++ ^payload
+func signDataCellHash(addr ton.AccountID, domain string, timestamp int64, schema string, payload *boc.Cell) ([]byte, error) {
+    c := boc.NewCell()
+    if err := c.WriteUint(opSignDataCell, 32); err != nil {
+        return nil, err
+    }
+    if err := c.WriteUint(uint64(crc32.ChecksumIEEE([]byte(schema))), 32); err != nil {
+        return nil, err
+    }
+    if err := c.WriteUint(uint64(timestamp), 64); err != nil {
+        return nil, err
+    }
+    if err := tlb.Marshal(c, addr.ToMsgAddress()); err != nil {
+        return nil, err
+    }
+    domainCell, err := snakeCell(encodeDomainDNS(domain))
+    if err != nil {
+        return nil, err
+    }
+    if err := c.AddRef(domainCell); err != nil {
+        return nil, err
+    }
+    if err := c.AddRef(payload); err != nil {
+        return nil, err
+    }
+    hash, err := c.Hash256()
+    if err != nil {
+        return nil, err
+    }
+    return hash[:], nil
+}
+
+// encodeDomainDNS encodes a domain the way TON DNS does: labels in reverse
+// order, each terminated by a zero byte.
+func encodeDomainDNS(domain string) []byte {
+    labels := strings.Split(domain, ".")
+    var out []byte
+    for i := len(labels) - 1; i >= 0; i-- {
+        out = append(out, labels[i]...)
+        out = append(out, 0)
+    }
+    return out
+}
+
+// snakeCell stores data in a chain of cells, 127 bytes per cell.
+func snakeCell(data []byte) (*boc.Cell, error) {
+    const chunk = 127
+    root := boc.NewCell()
+    c := root
+    for {
+        n := len(data)
+        if n > chunk {
+            n = chunk
+        }
+        if err := c.WriteBytes(data[:n]); err != nil {
+            return nil, err
+        }
+        data = data[n:]
+        if len(data) == 0 {
+            return root, nil
+        }
+        next := boc.NewCell()
+        if err := c.AddRef(next); err != nil {
+            return nil, err
+        }
+        c = next
+    }
+}
@@ -0,0 +1,517 @@
+// internal/usecase/txn.go
+package usecase
+
+import (
+    "context"
+    "crypto/ed25519"
+    "encoding/base64"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "math/big"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/hashicorp/vault/sdk/helper/locksutil"
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+const (
+    txnKindTon      = "ton"
+    txnKindJetton   = "jetton"
+    txnKindNFT      = "nft"
+    txnKindPlugin   = "plugin" // V4 plugin operation
+    txnKindMultisig = "multisig"
+
+    opJettonTransfer = 0x0f8a7ea5
+    opNFTTransfer    = 0x5fcc3d14
+
+    // how the wallet message reaches the wallet
+    messageTypeExternal = "signed_external"
+    messageTypeInternal = "signed_internal" // W5 only, delivered by a relayer
+)
+
+// maxWalletMessages is the number of internal messages a wallet accepts in one external message.
+var maxWalletMessages = map[wallet.Version]int{
+    wallet.V3R1: 4,
+    wallet.V3R2: 4,
+    wallet.V4R1: 4,
+    wallet.V4R2: 4,
+    wallet.V5R1: 255,
+}
+
+// outMessage is a decoded view of one internal message the wallet is asked to send,
+// kept next to the message itself so that every txn path is checked the same way.
+type outMessage struct {
+    Kind        string
+    Destination ton.AccountID // address the wallet message is sent to
+    Amount      uint64        // nanotons attached to the wallet message
+    Comment     string
+    Op          uint32 // op of the message body, when not implied by Kind
+
+    // jetton and NFT transfers
+    JettonAmount *big.Int
+    JettonMaster *ton.AccountID // verified against the jetton wallet
+    Recipient    *ton.AccountID // final recipient, or new owner of an NFT
+    // ResponseDestination receives the excess TON, unless it returns to the sender wallet
+    ResponseDestination *ton.AccountID
+
+    message wallet.Message
+}
+
+// sendModeCarryBalance is the send mode flag attaching the whole remaining balance of the wallet.
+const sendModeCarryBalance = 128
+
+// carriesBalance reports whether the message sends the whole wallet balance, whatever its Amount.
+func (m *outMessage) carriesBalance() bool {
+    return m.message.Mode&sendModeCarryBalance != 0
+}
+
+// txnFields returns the schema of the fields shared by all txn endpoints.
+func txnFields(extra map[string]*framework.FieldSchema) map[string]*framework.FieldSchema {
+    return mergeFields(map[string]*framework.FieldSchema{
+        "name":    {Type: framework.TypeString},
+        "version": versionField(),
+        "seqno": {
+            Type:        framework.TypeInt,
+            Description: "Current seqno of the wallet. With seqno 0 the wallet state init is attached to deploy it.",
+        },
+        "valid_until": {
+            Type:        framework.TypeInt,
+            Description: "(Optional) Unix time after which the message is rejected by the wallet. Defaults to now plus the default message lifetime and may not exceed the maximum lifetime.",
+        },
+        "idempotency_key": {
+            Type:        framework.TypeString,
+            Description: "(Optional) Key identifying the request. Retries with the same key and parameters return the first signed message.",
+        },
+        "message_type": {
+            Type:          framework.TypeString,
+            Description:   "(Optional) signed_external (default) returns an external message; signed_internal returns a W5 body for a relayer to deliver in an internal message.",
+            Default:       messageTypeExternal,
+            AllowedValues: []interface{}{messageTypeExternal, messageTypeInternal},
+        },
+    }, extra)
+}
+
+// txnParams holds the wallet message parameters shared by all txn endpoints.
+type txnParams struct {
+    seqno          uint32
+    validUntil     time.Time // zero: default lifetime
+    idempotencyKey string
+    messageType    string
+
+    // details describe what a custom body does and are added to the response
+    details map[string]interface{}
+    // actions are what a custom body does besides sending messages, such as
+    // installing a W5 extension. Policies see them and approvers are shown them.
+    actions []map[string]interface{}
+}
+
+func parseTxnParams(data *framework.FieldData) (*txnParams, error) {
+    seqno := data.Get("seqno").(int)
+    if seqno < 0 || int64(seqno) > int64(^uint32(0)) {
+        return nil, fmt.Errorf("seqno out of range")
+    }
+    p := &txnParams{
+        seqno:          uint32(seqno),
+        idempotencyKey: strings.TrimSpace(data.Get("idempotency_key").(string)),
+        messageType:    data.Get("message_type").(string),
+    }
+    if p.messageType != messageTypeExternal && p.messageType != messageTypeInternal {
+        return nil, fmt.Errorf("message_type must be %q or %q", messageTypeExternal, messageTypeInternal)
+    }
+    if v, ok := data.GetOk("valid_until"); ok {
+        p.validUntil = time.Unix(int64(v.(int)), 0)
+    }
+    return p, nil
+}
+
+// bodyBuilder builds the signed wallet body of requests other than plain transfers,
+// such as W5 extension actions or V4 plugin operations.
+type bodyBuilder func(priv ed25519.PrivateKey, ws walletSettings, mc wallet.MessageConfig) (*boc.Cell, error)
+
+// signTransfers wraps the messages into a wallet message signed by the key pair.
+func (b *Backend) signTransfers(
+    ctx context.Context,
+    req *logical.Request,
+    km *KeyManager,
+    kp *KeyPair,
+    params *txnParams,
+    msgs []*outMessage,
+) (*logical.Response, error) {
+    return b.signWalletRequest(ctx, req, km, kp, params, msgs, nil)
+}
+
+// signWalletRequest signs a wallet message whose body is built by build, or carries
+// msgs when build is nil. msgs are the transfers the body makes, checked against
+// the key‑manager controls either way. A request with an idempotency key is signed
+// once: retries get the stored response. A request waiting for approvals stores its
+// pending response, replaced by the signed one when the approved request is replayed.
+func (b *Backend) signWalletRequest(
+    ctx context.Context,
+    req *logical.Request,
+    km *KeyManager,
+    kp *KeyPair,
+    params *txnParams,
+    msgs []*outMessage,
+    build bodyBuilder,
+) (*logical.Response, error) {
+    approved, _ := ctx.Value(approvedRequestKey{}).(*pendingRequest)
+    if params.idempotencyKey == "" || (approved != nil && approved.RequestFingerprint == "") {
+        return b.signMessages(ctx, req, km, kp, params, msgs, build, approved)
+    }
+
+    lock := locksutil.LockForKey(b.idempotencyLocks, km.ServiceName+"/"+params.idempotencyKey)
+    lock.Lock()
+    defer lock.Unlock()
+    var fingerprint string
+    if approved != nil {
+        // the replay pins the key version and expiry: keep the record of the submitted request
+        fingerprint = approved.RequestFingerprint
+    } else {
+        var err error
+        if fingerprint, err = requestFingerprint(req); err != nil {
+            return nil, err
+        }
+        if resp, err := b.idempotentResponse(ctx, req, km.ServiceName, params.idempotencyKey, fingerprint); err != nil || resp != nil {
+            return resp, err
+        }
+    }
+    resp, err := b.signMessages(ctx, req, km, kp, params, msgs, build, approved)
+    if err != nil || resp == nil || resp.IsError() {
+        return resp, err
+    }
+    if err := b.storeIdempotentResponse(ctx, req, km.ServiceName, params.idempotencyKey, fingerprint, resp); err != nil {
+        return nil, err
+    }
+    return resp, nil
+}
+
+// signMessages checks the messages against the key‑manager controls and signs them,
+// unless they must wait for approvals. approved is set on the replay of an approved
+// request, which must sign what was approved.
+func (b *Backend) signMessages(
+    ctx context.Context,
+    req *logical.Request,
+    km *KeyManager,
+    kp *KeyPair,
+    params *txnParams,
+    msgs []*outMessage,
+    build bodyBuilder,
+    approved *pendingRequest,
+) (*logical.Response, error) {
+    if approved == nil {
+        if err := b.throttle(ctx, req, km); err != nil {
+            return nil, err
+        }
+    }
+    ws, err := kp.walletSettings()
+    if err != nil {
+        return nil, err
+    }
+    limit, ok := maxWalletMessages[ws.Version]
+    if !ok {
+        return nil, fmt.Errorf("signing for %s wallets is not supported", kp.WalletVersion)
+    }
+    if params.messageType == messageTypeInternal && ws.Version != wallet.V5R1 {
+        return logical.ErrorResponse("signed_internal messages need a v5R1 wallet, key version %d is %s", kp.ID, kp.WalletVersion), nil
+    }
+    cfg, err := b.config(ctx, req.Storage)
+    if err != nil {
+        return nil, err
+    }
+    if cfg.MaxBatchSize > 0 && cfg.MaxBatchSize < limit {
+        limit = cfg.MaxBatchSize
+    }
+    if (len(msgs) == 0 && build == nil) || len(msgs) > limit {
+        return logical.ErrorResponse("a transaction of key version %d carries 1 to %d messages, got %d", kp.ID, limit, len(msgs)), nil
+    }
+    now := time.Now()
+    lifetime := cfg.messageLifetime(km)
+    validUntil, err := lifetime.validUntil(params.validUntil, now)
+    if err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+
+    lock := locksutil.LockForKey(b.spendLocks, km.ServiceName)
+    lock.Lock()
+    defer lock.Unlock()
+    if err := km.checkDestinations(msgs); err != nil {
+        return logical.ErrorResponse(err.Error()), nil
+    }
+    if km.Policy != nil {
+        d, err := km.Policy.evaluate(policyInput{
+            ServiceName: km.ServiceName,
+            KeyVersion:  kp.ID,
+            EntityID:    req.EntityID,
+            Now:         time.Now(),
+            Messages:    msgs,
+            Actions:     params.actions,
+        })
+        if err != nil {
+            return nil, err
+        }
+        if !d.Allowed {
+            return logical.ErrorResponse("denied by signing policy: %s", d.Reason), nil
+        }
+    }
+    record, err := b.enforceSpendingLimits(ctx, req.Storage, km, kp, msgs)
+    if err != nil {
+        var limitErr limitError
+        if errors.As(err, &limitErr) {
+            return logical.ErrorResponse(limitErr.Error()), nil
+        }
+        return nil, err
+    }
+    previews := make([]map[string]interface{}, len(msgs))
+    for i, m := range msgs {
+        previews[i] = m.preview()
+    }
+    if approved != nil {
+        if err := approved.matches(kp, validUntil, previews, params); err != nil {
+            return logical.ErrorResponse(err.Error()), nil
+        }
+    } else {
+        amounts, err := assetAmounts(msgs, km.Approvals.hasJettonThresholds())
+        if err != nil {
+            return logical.ErrorResponse(err.Error()), nil
+        }
+        if km.Approvals.requires(amounts) || km.Approvals.requiresBalance(msgs) {
+            // without an explicit valid_until, the message stays valid while it waits for approvals
+            if params.validUntil.IsZero() {
+                validUntil = lifetime.pendingValidUntil(km.Approvals.TTL, now)
+            }
+            return b.submitForApproval(ctx, req, km, kp, params, time.Unix(validUntil.Unix(), 0), msgs)
+        }
+    }
+
+    priv, err := kp.privateKey()
+    if err != nil {
+        return nil, err
+    }
+    defer zeroSeed(priv)
+
+    w, err := wallet.New(priv, ws.Version, nil, ws.options()...)
+    if err != nil {
+        return nil, fmt.Errorf("failed to init wallet: %w", err)
+    }
+    mc := wallet.MessageConfig{
+        Seqno:      params.seqno,
+        ValidUntil: validUntil,
+        V5MsgType:  wallet.V5MsgTypeSignedExternal,
+    }
+    if params.messageType == messageTypeInternal {
+        mc.V5MsgType = wallet.V5MsgTypeSignedInternal
+    }
+    var body *boc.Cell
+    if build != nil {
+        body, err = build(priv, ws, mc)
+    } else {
+        sendables := make([]wallet.Sendable, len(msgs))
+        for i, m := range msgs {
+            sendables[i] = m.message
+        }
+        body, err = w.CreateMessageBody(mc, sendables...)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to build wallet message: %w", err)
+    }
+
+    var init *tlb.StateInit
+    if params.seqno == 0 {
+        if init, err = w.StateInit(); err != nil {
+            return nil, fmt.Errorf("failed to build wallet state init: %w", err)
+        }
+    }
+    out := map[string]interface{}{
+        "version":      kp.ID,
+        "address":      kp.Address,
+        "seqno":        params.seqno,
+        "valid_until":  validUntil.Unix(),
+        "expires_at":   validUntil.UTC().Format(time.RFC3339),
+        "message_type": params.messageType,
+    }
+    for k, v := range params.details {
+        out[k] = v
+    }
+    // a signed external is sent as is; a signed internal body is handed to a
+    // relayer, which attaches it and the state init to its own internal message
+    cell := body
+    if params.messageType == messageTypeExternal {
+        ext, err := ton.CreateExternalMessage(w.GetAddress(), body, init, tlb.VarUInteger16{})
+        if err != nil {
+            return nil, fmt.Errorf("failed to build external message: %w", err)
+        }
+        cell = boc.NewCell()
+        if err := tlb.Marshal(cell, ext); err != nil {
+            return nil, fmt.Errorf("failed to marshal external message: %w", err)
+        }
+    } else if init != nil {
+        initCell := boc.NewCell()
+        if err := tlb.Marshal(initCell, init); err != nil {
+            return nil, fmt.Errorf("failed to marshal wallet state init: %w", err)
+        }
+        if out["state_init"], err = initCell.ToBocBase64(); err != nil {
+            return nil, err
+        }
+    }
+    hash, err := cell.Hash256()
+    if err != nil {
+        return nil, err
+    }
+    raw, err := cell.ToBoc()
+    if err != nil {
+        return nil, err
+    }
+    out["msg_id"] = hex.EncodeToString(hash[:])
+    if params.messageType == messageTypeExternal {
+        out["signed_boc"] = base64.StdEncoding.EncodeToString(raw)
+    } else {
+        out["signed_body"] = base64.StdEncoding.EncodeToString(raw)
+    }
+    if err := record(); err != nil {
+        return nil, err
+    }
+    seqno := params.seqno
+    if err := b.recordHistory(ctx, req, km, kp, &historyEntry{
+        Hash:     hex.EncodeToString(hash[:]),
+        Seqno:    &seqno,
+        Messages: previews,
+    }); err != nil {
+        return nil, err
+    }
+
+    return &logical.Response{Data: out}, nil
+}
+
+// parseDestination parses a friendly or raw address. Unless overridden by the
+// caller, messages bounce for bounceable friendly and for raw addresses.
+func parseDestination(data *framework.FieldData, field, bounceField string) (ton.AccountID, bool, error) {
+    s := strings.TrimSpace(data.Get(field).(string))
+    if s == "" {
+        return ton.AccountID{}, false, fmt.Errorf("%s is required", field)
+    }
+    id, err := ton.ParseAccountID(s)
+    if err != nil {
+        return ton.AccountID{}, false, fmt.Errorf("invalid %s %q: %w", field, s, err)
+    }
+    bounce := true
+    if !strings.Contains(s, ":") {
+        bounce = isBounceable(s)
+    }
+    if bounceField != "" {
+        if v, ok := data.GetOk(bounceField); ok {
+            bounce = v.(bool)
+        }
+    }
+    return id, bounce, nil
+}
+
+// isBounceable reads the bounceable flag of a friendly address.
+func isBounceable(s string) bool {
+    s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+    raw, err := base64.URLEncoding.DecodeString(s)
+    if err != nil || len(raw) == 0 {
+        return true
+    }
+    return raw[0]&0x40 == 0
+}
+
+// parseAmount parses a non-negative integer amount in base units.
+func parseAmount(s, field string) (*big.Int, error) {
+    v, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+    if !ok || v.Sign() < 0 {
+        return nil, fmt.Errorf("%s must be a non-negative integer, got %q", field, s)
+    }
+    return v, nil
+}
+
+// parseNanotons parses a TON amount given in nanotons.
+func parseNanotons(s, field string) (uint64, error) {
+    v, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+    if err != nil {
+        return 0, fmt.Errorf("%s must be an amount in nanotons, got %q", field, s)
+    }
+    return v, nil
+}
+
+// commentCell builds a text comment message body.
+func commentCell(comment string) (*boc.Cell, error) {
+    c := boc.NewCell()
+    if err := tlb.Marshal(c, wallet.TextComment(comment)); err != nil {
+        return nil, fmt.Errorf("failed to encode comment: %w", err)
+    }
+    return c, nil
+}
+
+// jettonTransferBody builds a TEP-74 jetton transfer body.
+func jettonTransferBody(queryID uint64, amount *big.Int, dest, response ton.AccountID, forwardTon uint64, comment string) (*boc.Cell, error) {
+    c := boc.NewCell()
+    if err := c.WriteUint(opJettonTransfer, 32); err != nil {
+        return nil, err
+    }
+    if err := c.WriteUint(queryID, 64); err != nil {
+        return nil, err
+    }
+    if err := tlb.Marshal(c, tlb.VarUInteger16(*amount)); err != nil {
+        return nil, err
+    }
+    if err := tlb.Marshal(c, dest.ToMsgAddress()); err != nil {
+        return nil, err
+    }
+    if err := tlb.Marshal(c, response.ToMsgAddress()); err != nil {
+        return nil, err
+    }
+    if err := c.WriteBit(false); err != nil { // no custom_payload
+        return nil, err
+    }
+    if err := tlb.Marshal(c, tlb.Grams(forwardTon)); err != nil {
+        return nil, err
+    }
+    return c, writeForwardPayload(c, comment)
+}
+
+// nftTransferBody builds a TEP-62 NFT transfer body.
+func nftTransferBody(queryID uint64, newOwner, response ton.AccountID, forwardTon uint64, comment string) (*boc.Cell, error) {
+    c := boc.NewCell()
+    if err := c.WriteUint(opNFTTransfer, 32); err != nil {
+        return nil, err
+    }
+    if err := c.WriteUint(queryID, 64); err != nil {
+        return nil, err
+    }
+    if err := tlb.Marshal(c, newOwner.ToMsgAddress()); err != nil {
+        return nil, err
+    }
+    if err := tlb.Marshal(c, response.ToMsgAddress()); err != nil {
+        return nil, err
+    }
+    if err := c.WriteBit(false); err != nil { // no custom_payload
+        return nil, err
+    }
+    if err := tlb.Marshal(c, tlb.Grams(forwardTon)); err != nil {
+        return nil, err
+    }
+    return c, writeForwardPayload(c, comment)
+}
+
+// writeForwardPayload writes a forward_payload:(Either Cell ^Cell) holding an optional comment.
+func writeForwardPayload(c *boc.Cell, comment string) error {
+    if comment == "" {
+        return c.WriteBit(false) // empty inline forward_payload
+    }
+    payload, err := commentCell(comment)
+    if err != nil {
+        return err
+    }
+    if err := c.WriteBit(true); err != nil {
+        return err
+    }
+    return c.AddRef(payload)
+}
@@ -0,0 +1,88 @@
+// internal/usecase/txn_test.go
+
+package usecase
+
+import (
+    "context"
+    "crypto/ed25519"
+    "encoding/hex"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/vault/sdk/logical"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+func TestSignedInternal(t *testing.T) {
+    b, storage := newTestBackend(t)
+
+    for name, version := range map[string]string{"w5": "v5R1", "w4": "v4R2"} {
+        req := logical.TestRequest(t, logical.UpdateOperation, "key-managers")
+        req.Storage = storage
+        req.Data = map[string]interface{}{"serviceName": name, "wallet_version": version}
+        resp, err := b.HandleRequest(context.Background(), req)
+        require.NoError(t, err)
+        require.False(t, resp.IsError(), "%v", resp.Data)
+    }
+
+    transfer := func(name string, seqno int) *logical.Response {
+        return doRequest(t, b, storage, logical.CreateOperation, "key-managers/"+name+"/txn/ton/transfer", "", map[string]interface{}{
+            "destination":  testDestination,
+            "amount":       "1000",
+            "seqno":        seqno,
+            "valid_until":  time.Now().Add(time.Minute).Unix(),
+            "message_type": "signed_internal",
+        })
+    }
+
+    resp := transfer("w5", 7)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.Equal(t, "signed_internal", resp.Data["message_type"])
+    assert.NotContains(t, resp.Data, "signed_boc")
+    assert.NotContains(t, resp.Data, "state_init")
+
+    cells, err := boc.DeserializeBocBase64(resp.Data["signed_body"].(string))
+    require.NoError(t, err)
+    body := cells[0]
+    hash, err := body.Hash256()
+    require.NoError(t, err)
+    assert.Equal(t, hex.EncodeToString(hash[:]), resp.Data["msg_id"])
+
+    var msg wallet.MessageV5
+    require.NoError(t, tlb.Unmarshal(body, &msg))
+    require.NotNil(t, msg.SignedInternal)
+    assert.EqualValues(t, 7, msg.SignedInternal.Seqno)
+    assert.EqualValues(t, resp.Data["valid_until"], msg.SignedInternal.ValidUntil)
+    require.NotNil(t, msg.SignedInternal.Actions)
+    assert.Len(t, *msg.SignedInternal.Actions, 1)
+
+    // the signature covers the body without its last 512 bits
+    km, err := b.retrieveKeyManager(context.Background(), &logical.Request{Storage: storage}, "w5")
+    require.NoError(t, err)
+    pub, err := hex.DecodeString(km.keyPairByID(km.CurrentVersion).PublicKey)
+    require.NoError(t, err)
+    body.ResetCounters()
+    signed, err := body.ReadBits(body.BitSize() - 512)
+    require.NoError(t, err)
+    unsigned := boc.NewCell()
+    require.NoError(t, unsigned.WriteBitString(signed))
+    for _, ref := range body.Refs() {
+        require.NoError(t, unsigned.AddRef(ref))
+    }
+    unsignedHash, err := unsigned.Hash256()
+    require.NoError(t, err)
+    assert.True(t, ed25519.Verify(pub, unsignedHash[:], msg.SignedInternal.Signature[:]))
+
+    // the relayer deploys an undeployed wallet with the state init
+    resp = transfer("w5", 0)
+    require.False(t, resp.IsError(), "%v", resp.Data)
+    assert.NotEmpty(t, resp.Data["state_init"])
+
+    resp = transfer("w4", 1)
+    require.True(t, resp.IsError())
+    assert.Contains(t, resp.Error().Error(), "v5R1")
+}
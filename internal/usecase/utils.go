@@ -8,12 +8,34 @@ import (
     "github.com/tonkeeper/tongo/wallet"
 )
 
-// deriveTonAddress берёт Ed25519 pub‑key и возвращает bounceable‑friendly TON‑адрес.
-func deriveTonAddress(pub ed25519.PublicKey) string {
-    addr, err := wallet.GenerateWalletAddress(pub, wallet.V4R2, nil, 0, nil)
+// deriveTonAddress берёт Ed25519 pub‑key и возвращает bounceable‑friendly TON‑адрес кошелька.
+func deriveTonAddress(pub ed25519.PublicKey, ws walletSettings) (string, error) {
+    if err := ws.validate(); err != nil {
+        return "", err
+    }
+    globalID := networkGlobalID(ws.Network)
+    addr, err := wallet.GenerateWalletAddress(pub, ws.Version, &globalID, ws.Workchain, nil)
     if err != nil {
-        panic(fmt.Sprintf("deriveTonAddress: %v", err))
+        return "", fmt.Errorf("deriveTonAddress: %w", err)
+    }
+    return addr.ToHuman(true, ws.Network == networkTestnet), nil
+}
+
+// options returns the tongo wallet options matching the settings.
+func (ws walletSettings) options() []wallet.Option {
+    return []wallet.Option{
+        wallet.WithWorkchain(ws.Workchain),
+        wallet.WithNetworkGlobalID(networkGlobalID(ws.Network)),
     }
-    return addr.ToHuman(true, false)
 }
 
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+    for _, v := range list {
+        if v == s {
+            return true
+        }
+    }
+    return false
+}
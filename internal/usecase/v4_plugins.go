@@ -0,0 +1,129 @@
+// internal/usecase/v4_plugins.go
+package usecase
+
+import (
+    "crypto/ed25519"
+    "fmt"
+
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+// V4 wallet operations on plugins
+const (
+    pluginDeploy  = "deploy"
+    pluginInstall = "install"
+    pluginRemove  = "remove"
+
+    opV4DeployPlugin  = 1
+    opV4InstallPlugin = 2
+    opV4RemovePlugin  = 3
+
+    // ops of the message a V4 wallet sends to an installed or removed plugin
+    opPluginNotify   = 0x6e6f7465 // "note"
+    opPluginDestruct = 0x64737472 // "dstr"
+)
+
+// v4PluginRequest is a plugin operation of a V4 wallet.
+type v4PluginRequest struct {
+    Operation string
+    Plugin    ton.AccountID
+    Amount    uint64 // plugin balance on deploy, nanotons sent to the plugin otherwise
+
+    StateInit *boc.Cell // deploy
+    Body      *boc.Cell // deploy
+    QueryID   uint64    // install and remove
+}
+
+// message is the transfer the wallet makes to the plugin.
+func (p *v4PluginRequest) message() *outMessage {
+    m := &outMessage{Kind: txnKindPlugin, Destination: p.Plugin, Amount: p.Amount}
+    switch p.Operation {
+    case pluginInstall:
+        m.Op = opPluginNotify
+    case pluginRemove:
+        m.Op = opPluginDestruct
+    }
+    return m
+}
+
+// preview describes the operation in responses.
+func (p *v4PluginRequest) preview() map[string]interface{} {
+    out := map[string]interface{}{
+        "operation": p.Operation,
+        "plugin":    p.Plugin.ToRaw(),
+        "amount":    fmt.Sprint(p.Amount),
+    }
+    if p.Operation != pluginDeploy {
+        out["query_id"] = p.QueryID
+    }
+    return out
+}
+
+// body builds the signed V4 body: subwallet_id, valid_until, seqno and op, followed by
+//   op 1: workchain:int8 balance:Grams state_init:^Cell body:^Cell
+//   op 2, 3: workchain:int8 address:uint256 amount:Grams query_id:uint64
+// and prefixed by the signature of the body hash.
+func (p *v4PluginRequest) body() bodyBuilder {
+    return func(priv ed25519.PrivateKey, ws walletSettings, mc wallet.MessageConfig) (*boc.Cell, error) {
+        c := boc.NewCell()
+        if err := tlb.Marshal(c, struct {
+            SubWalletID uint32
+            ValidUntil  uint32
+            Seqno       uint32
+        }{uint32(wallet.DefaultSubWallet + ws.Workchain), uint32(mc.ValidUntil.Unix()), mc.Seqno}); err != nil {
+            return nil, err
+        }
+        var err error
+        switch p.Operation {
+        case pluginDeploy:
+            err = tlb.Marshal(c, struct {
+                Op        uint8
+                Workchain int8
+                Balance   tlb.Grams
+                StateInit tlb.Any `tlb:"^"`
+                Body      tlb.Any `tlb:"^"`
+            }{opV4DeployPlugin, int8(p.Plugin.Workchain), tlb.Grams(p.Amount), tlb.Any(*p.StateInit), tlb.Any(*p.Body)})
+        case pluginInstall, pluginRemove:
+            op := uint8(opV4InstallPlugin)
+            if p.Operation == pluginRemove {
+                op = opV4RemovePlugin
+            }
+            err = tlb.Marshal(c, struct {
+                Op        uint8
+                Workchain int8
+                Address   tlb.Bits256
+                Amount    tlb.Grams
+                QueryID   uint64
+            }{op, int8(p.Plugin.Workchain), tlb.Bits256(p.Plugin.Address), tlb.Grams(p.Amount), p.QueryID})
+        default:
+            err = fmt.Errorf("unknown plugin operation %q", p.Operation)
+        }
+        if err != nil {
+            return nil, err
+        }
+
+        sig, err := c.Sign(priv)
+        if err != nil {
+            return nil, err
+        }
+        signed := wallet.SignedMsgBody{Message: tlb.Any(*c)}
+        copy(signed.Sign[:], sig)
+        out := boc.NewCell()
+        if err := tlb.Marshal(out, signed); err != nil {
+            return nil, err
+        }
+        return out, nil
+    }
+}
+
+// pluginAddress returns the address a plugin state init deploys to.
+func pluginAddress(workchain int, init *boc.Cell) (ton.AccountID, error) {
+    hash, err := init.Hash256()
+    if err != nil {
+        return ton.AccountID{}, err
+    }
+    return ton.AccountID{Workchain: int32(workchain), Address: hash}, nil
+}
@@ -0,0 +1,101 @@
+// internal/usecase/w5.go
+package usecase
+
+import (
+    "crypto/ed25519"
+    "fmt"
+    "strings"
+
+    "github.com/hashicorp/vault/sdk/framework"
+    "github.com/tonkeeper/tongo/boc"
+    "github.com/tonkeeper/tongo/tlb"
+    "github.com/tonkeeper/tongo/ton"
+    "github.com/tonkeeper/tongo/wallet"
+)
+
+// W5 extended actions
+const (
+    w5AddExtension        = "add_extension"
+    w5RemoveExtension     = "remove_extension"
+    w5SetSignatureAllowed = "set_signature_allowed"
+)
+
+// w5ActionFields returns the schema of one W5 extended action.
+func w5ActionFields() map[string]*framework.FieldSchema {
+    return map[string]*framework.FieldSchema{
+        "action": {
+            Type:          framework.TypeString,
+            Description:   "add_extension, remove_extension or set_signature_allowed.",
+            AllowedValues: []interface{}{w5AddExtension, w5RemoveExtension, w5SetSignatureAllowed},
+        },
+        "address": {
+            Type:        framework.TypeString,
+            Description: "Extension address, for add_extension and remove_extension.",
+        },
+        "allowed": {
+            Type:        framework.TypeBool,
+            Description: "Whether the wallet accepts signed messages, for set_signature_allowed.",
+        },
+    }
+}
+
+// parseW5Actions builds the extended actions of a request. disablesSignature
+// reports whether one of them turns signature authentication off.
+func parseW5Actions(raw []interface{}) (actions wallet.W5ExtendedActions, previews []map[string]interface{}, disablesSignature bool, err error) {
+    if len(raw) == 0 {
+        return nil, nil, false, fmt.Errorf("actions is required")
+    }
+    for i, item := range raw {
+        fields, ok := item.(map[string]interface{})
+        if !ok {
+            return nil, nil, false, fmt.Errorf("actions[%d] must be an object", i)
+        }
+        data := &framework.FieldData{Raw: fields, Schema: w5ActionFields()}
+        if err := data.Validate(); err != nil {
+            return nil, nil, false, fmt.Errorf("actions[%d]: %w", i, err)
+        }
+        var a wallet.W5ExtendedAction
+        preview := map[string]interface{}{"action": data.Get("action").(string)}
+        switch data.Get("action").(string) {
+        case w5AddExtension, w5RemoveExtension:
+            s := strings.TrimSpace(data.Get("address").(string))
+            id, err := ton.ParseAccountID(s)
+            if err != nil {
+                return nil, nil, false, fmt.Errorf("actions[%d]: invalid address %q: %w", i, s, err)
+            }
+            addr := struct{ Addr tlb.MsgAddress }{Addr: id.ToMsgAddress()}
+            if data.Get("action").(string) == w5AddExtension {
+                a.SumType, a.AddExtension = "AddExtension", &addr
+            } else {
+                a.SumType, a.RemoveExtension = "RemoveExtension", &addr
+            }
+            preview["address"] = id.ToRaw()
+        case w5SetSignatureAllowed:
+            v, ok := data.GetOk("allowed")
+            if !ok {
+                return nil, nil, false, fmt.Errorf("actions[%d]: allowed is required for %s", i, w5SetSignatureAllowed)
+            }
+            allowed := v.(bool)
+            a.SumType, a.SetSignatureAllowed = "SetSignatureAllowed", &struct{ Allowed bool }{Allowed: allowed}
+            disablesSignature = disablesSignature || !allowed
+            preview["allowed"] = allowed
+        default:
+            return nil, nil, false, fmt.Errorf("actions[%d]: action is required", i)
+        }
+        actions = append(actions, a)
+        previews = append(previews, preview)
+    }
+    return actions, previews, disablesSignature, nil
+}
+
+// w5ExtensionBody builds a W5 body carrying extended actions and no out messages.
+func w5ExtensionBody(actions wallet.W5ExtendedActions) bodyBuilder {
+    return func(priv ed25519.PrivateKey, ws walletSettings, mc wallet.MessageConfig) (*boc.Cell, error) {
+        globalID := networkGlobalID(ws.Network)
+        w := wallet.NewWalletV5R1(priv.Public().(ed25519.PublicKey), wallet.Options{
+            Workchain:       &ws.Workchain,
+            NetworkGlobalID: &globalID,
+        })
+        return w.CreateSignedMsgBodyCell(priv, nil, &actions, mc)
+    }
+}